@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"ride-sharing/config"
+	adminModel "ride-sharing/internal/domains/admin/models"
+	adminRepository "ride-sharing/internal/domains/admin/repository"
+	riderRepository "ride-sharing/internal/domains/riders/repository"
+	userRepository "ride-sharing/internal/domains/users/repository"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/events"
+	"ride-sharing/internal/pkg/password"
+
+	"gorm.io/gorm"
+)
+
+func runCreateAdmin(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := flags.String("email", "", "admin email (required)")
+	name := flags.String("name", "", "full name (required)")
+	pw := flags.String("password", "", "password; a random one is generated and printed when empty")
+	flags.Parse(args)
+
+	if *email == "" || *name == "" {
+		flags.Usage()
+		return errors.New("-email and -name are required")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	policy, err := setupPasswords(cfg)
+	if err != nil {
+		return err
+	}
+
+	repo := adminRepository.NewAdminRepository(db)
+	existing, err := repo.GetByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("admin %s already exists", *email)
+	}
+
+	plain, generated, err := choosePassword(policy, *pw)
+	if err != nil {
+		return err
+	}
+	hashed, err := password.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	admin := &adminModel.Admin{
+		FullName:          *name,
+		Email:             *email,
+		Password:          hashed,
+		Active:            true,
+		PasswordChangedAt: &now,
+	}
+	if err := repo.Create(ctx, admin); err != nil {
+		return err
+	}
+
+	fmt.Printf("created admin %s (%s)\n", admin.Email, admin.ID)
+	if generated {
+		fmt.Printf("password: %s\n", plain)
+	}
+	return nil
+}
+
+func runResetPassword(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	userType := flags.String("type", string(auth.UserTypeUser), "account type: user, rider or admin")
+	email := flags.String("email", "", "account email (required)")
+	pw := flags.String("password", "", "new password; a random one is generated and printed when empty")
+	flags.Parse(args)
+
+	if *email == "" {
+		flags.Usage()
+		return errors.New("-email is required")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	policy, err := setupPasswords(cfg)
+	if err != nil {
+		return err
+	}
+
+	target, err := findAccount(ctx, db, auth.UserType(*userType), *email, policy.HistorySize())
+	if err != nil {
+		return err
+	}
+
+	plain, generated, err := choosePassword(policy, *pw)
+	if err != nil {
+		return err
+	}
+	hashed, err := password.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+
+	// Changing the password also moves password_changed_at, which signs the account out everywhere
+	if err := target.changePassword(ctx, hashed); err != nil {
+		return err
+	}
+	if err := evictIdentity(ctx, cfg, auth.UserType(*userType), target.id); err != nil {
+		return err
+	}
+
+	fmt.Printf("password reset for %s %s (%s)\n", *userType, *email, target.id)
+	if generated {
+		fmt.Printf("password: %s\n", plain)
+	}
+	return nil
+}
+
+func runRevokeUserSessions(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("revoke-user-sessions", flag.ExitOnError)
+	userType := flags.String("type", string(auth.UserTypeUser), "account type: user, rider or admin")
+	email := flags.String("email", "", "account email (required)")
+	flags.Parse(args)
+
+	if *email == "" {
+		flags.Usage()
+		return errors.New("-email is required")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+
+	target, err := findAccount(ctx, db, auth.UserType(*userType), *email, 0)
+	if err != nil {
+		return err
+	}
+	if err := target.revokeSessions(ctx); err != nil {
+		return err
+	}
+	if err := evictIdentity(ctx, cfg, auth.UserType(*userType), target.id); err != nil {
+		return err
+	}
+
+	fmt.Printf("revoked all sessions of %s %s (%s)\n", *userType, *email, target.id)
+	return nil
+}
+
+func runSuspendAccount(ctx context.Context, cfg *config.Config, args []string) error {
+	return setAccountSuspended(ctx, cfg, "suspend-account", true, args)
+}
+
+func runUnsuspendAccount(ctx context.Context, cfg *config.Config, args []string) error {
+	return setAccountSuspended(ctx, cfg, "unsuspend-account", false, args)
+}
+
+// setAccountSuspended backs suspend-account and unsuspend-account. A suspended account keeps
+// its data but cannot sign in, refresh or use existing tokens.
+func setAccountSuspended(ctx context.Context, cfg *config.Config, name string, suspended bool, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	userType := flags.String("type", string(auth.UserTypeUser), "account type: user, rider or admin")
+	email := flags.String("email", "", "account email (required)")
+	flags.Parse(args)
+
+	if *email == "" {
+		flags.Usage()
+		return errors.New("-email is required")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+
+	target, err := findAccount(ctx, db, auth.UserType(*userType), *email, 0)
+	if err != nil {
+		return err
+	}
+	if err := target.setSuspended(ctx, suspended); err != nil {
+		return err
+	}
+	if err := evictIdentity(ctx, cfg, auth.UserType(*userType), target.id); err != nil {
+		return err
+	}
+
+	if suspended {
+		fmt.Printf("suspended %s %s (%s)\n", *userType, *email, target.id)
+	} else {
+		fmt.Printf("lifted the suspension of %s %s (%s)\n", *userType, *email, target.id)
+	}
+	return nil
+}
+
+// account adapts the per type repositories to the operations the commands need
+type account struct {
+	id             string
+	changePassword func(ctx context.Context, hashedPassword string) error
+	revokeSessions func(ctx context.Context) error
+	setSuspended   func(ctx context.Context, suspended bool) error
+}
+
+func findAccount(ctx context.Context, db *gorm.DB, userType auth.UserType, email string, historySize int) (*account, error) {
+	notFound := fmt.Errorf("no %s with email %s", userType, email)
+
+	switch userType {
+	case auth.UserTypeUser:
+		repo := userRepository.NewUserRepository(db)
+		user, err := repo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, notFound
+		}
+		return &account{
+			id: user.ID.String(),
+			changePassword: func(ctx context.Context, hashedPassword string) error {
+				changed, err := events.New(ctx, events.UserPasswordChanged, user.ID.String(), events.UserPasswordChangedData{
+					UserID:    user.ID.String(),
+					Reason:    "reset",
+					ChangedAt: time.Now().UTC(),
+				})
+				if err != nil {
+					return err
+				}
+				_, err = repo.ChangePassword(ctx, user, hashedPassword, historySize, changed)
+				return err
+			},
+			revokeSessions: func(ctx context.Context) error {
+				return repo.RevokeSessions(ctx, user)
+			},
+			setSuspended: func(ctx context.Context, suspended bool) error {
+				return repo.SetSuspended(ctx, user, suspended)
+			},
+		}, nil
+
+	case auth.UserTypeRider:
+		repo := riderRepository.NewRiderRepository(db)
+		rider, err := repo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if rider == nil {
+			return nil, notFound
+		}
+		return &account{
+			id: rider.ID.String(),
+			changePassword: func(ctx context.Context, hashedPassword string) error {
+				return repo.ChangePassword(ctx, rider, hashedPassword)
+			},
+			revokeSessions: func(ctx context.Context) error {
+				return repo.RevokeSessions(ctx, rider)
+			},
+			setSuspended: func(ctx context.Context, suspended bool) error {
+				return repo.SetSuspended(ctx, rider, suspended)
+			},
+		}, nil
+
+	case auth.UserTypeAdmin:
+		repo := adminRepository.NewAdminRepository(db)
+		admin, err := repo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if admin == nil {
+			return nil, notFound
+		}
+		return &account{
+			id: admin.ID.String(),
+			changePassword: func(ctx context.Context, hashedPassword string) error {
+				return repo.ChangePassword(ctx, admin, hashedPassword)
+			},
+			revokeSessions: func(ctx context.Context) error {
+				return repo.RevokeSessions(ctx, admin)
+			},
+			setSuspended: func(ctx context.Context, suspended bool) error {
+				return repo.SetSuspended(ctx, admin, suspended)
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported account type %q", userType)
+}
+
+// choosePassword validates the given password, or generates one when it is empty
+func choosePassword(policy *password.Policy, given string) (string, bool, error) {
+	if given != "" {
+		return given, false, checkPassword(policy, given)
+	}
+	generated, err := generatePassword()
+	return generated, true, err
+}
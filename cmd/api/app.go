@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"ride-sharing/config"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/database"
+	"ride-sharing/internal/pkg/password"
+	"ride-sharing/internal/pkg/redis"
+	"ride-sharing/internal/routes"
+
+	"gorm.io/gorm"
+)
+
+// openDatabase connects to Postgres; every command that touches the database goes through it
+func openDatabase(cfg *config.Config) (*gorm.DB, error) {
+	db, err := database.NewPostgresDB(database.DBConfig{
+		Host:     cfg.DB.Host,
+		Port:     cfg.DB.Port,
+		User:     cfg.DB.User,
+		Password: cfg.DB.Password,
+		Name:     cfg.DB.Name,
+
+		MaxOpenConns:    cfg.DB.MaxOpenConns,
+		MaxIdleConns:    cfg.DB.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(cfg.DB.ConnMaxLifetimeMinutes) * time.Minute,
+		ConnectTimeout:  time.Duration(cfg.DB.ConnectTimeoutMs) * time.Millisecond,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return db, nil
+}
+
+// evictIdentity drops the identity the server caches for an account. Commands that change
+// an account call it after the change is committed, otherwise the server keeps accepting
+// tokens the change should have revoked until the cache entry expires.
+func evictIdentity(ctx context.Context, cfg *config.Config, userType auth.UserType, id string) error {
+	redisClient := redis.New(cfg)
+	defer redisClient.Close()
+
+	if err := routes.NewIdentityCache(redisClient, cfg).Evict(ctx, userType, id); err != nil {
+		return fmt.Errorf("change saved, but the cached identity could not be invalidated; existing sessions stay valid for up to %ds: %w",
+			cfg.IdentityCache.TTLSeconds, err)
+	}
+	return nil
+}
+
+// setupPasswords installs the configured hasher as the package default and builds the
+// password policy, so commands hash and validate passwords exactly like the server does
+func setupPasswords(cfg *config.Config) (*password.Policy, error) {
+	// bcrypt stays available to verify older hashes
+	argon2Hasher := password.NewArgon2idHasher(password.Argon2Params{
+		Memory:      uint32(cfg.Password.Argon2Memory),
+		Iterations:  uint32(cfg.Password.Argon2Time),
+		Parallelism: uint8(cfg.Password.Argon2Threads),
+	})
+	bcryptHasher := password.NewBcryptHasher(cfg.Password.BcryptCost)
+	if cfg.Password.HashAlgorithm == "bcrypt" {
+		password.SetDefault(password.NewManager(bcryptHasher, argon2Hasher))
+	} else {
+		password.SetDefault(password.NewManager(argon2Hasher, bcryptHasher))
+	}
+
+	policy, err := password.NewPolicy(password.PolicyConfig{
+		MinLength:      cfg.Password.MinLength,
+		RequireUpper:   cfg.Password.RequireUpper,
+		RequireLower:   cfg.Password.RequireLower,
+		RequireDigit:   cfg.Password.RequireDigit,
+		RequireSpecial: cfg.Password.RequireSpecial,
+		MaxAge:         time.Duration(cfg.Password.MaxAgeDays) * 24 * time.Hour,
+		HistorySize:    cfg.Password.HistorySize,
+		BreachListFile: cfg.Password.BreachListFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load password policy: %w", err)
+	}
+	return policy, nil
+}
+
+// checkPassword applies the same rules the API enforces on user supplied passwords
+func checkPassword(policy *password.Policy, pw string) error {
+	if !policy.MeetsRules(pw) {
+		return fmt.Errorf("password does not meet the policy: %s", policy.Description())
+	}
+	if policy.IsBreached(pw) {
+		return fmt.Errorf("password has appeared in a data breach")
+	}
+	return nil
+}
+
+// generatePassword returns a random password with every character class, long enough
+// for any policy this service is likely to be configured with
+func generatePassword() (string, error) {
+	classes := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnopqrstuvwxyz",
+		"23456789",
+		"!@#$%^&*-_=+",
+	}
+	const length = 24
+
+	buf := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		class := classes[i%len(classes)]
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(class))))
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, class[n.Int64()])
+	}
+
+	// Shuffle so the class order is not predictable
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		buf[i], buf[j.Int64()] = buf[j.Int64()], buf[i]
+	}
+	return string(buf), nil
+}
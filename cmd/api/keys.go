@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"ride-sharing/config"
+	"ride-sharing/internal/domains/serviceaccounts/dto"
+	serviceAccountRepository "ride-sharing/internal/domains/serviceaccounts/repository"
+	serviceAccountService "ride-sharing/internal/domains/serviceaccounts/service"
+)
+
+// runRotateKeys rotates service account API keys. The new raw keys are printed once;
+// the old keys keep working for the grace period so partners can roll them out.
+func runRotateKeys(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	accountID := flags.String("account", "", "service account ID (required)")
+	keyIDs := flags.String("keys", "", "comma separated API key IDs to rotate (required)")
+	grace := flags.Int("grace-minutes", 60, "how long the old keys keep working, capped at their own expiry")
+	flags.Parse(args)
+
+	if *accountID == "" || *keyIDs == "" {
+		flags.Usage()
+		return errors.New("-account and -keys are required")
+	}
+	if *grace < 0 || *grace > 10080 {
+		return errors.New("-grace-minutes must be between 0 and 10080")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	// Rotation never touches the per key rate limiter, so no Redis connection is needed
+	svc := serviceAccountService.NewServiceAccountService(serviceAccountRepository.NewServiceAccountRepository(db), nil)
+
+	var errs []error
+	for _, keyID := range strings.Split(*keyIDs, ",") {
+		keyID = strings.TrimSpace(keyID)
+		if keyID == "" {
+			continue
+		}
+		rotated, appErr := svc.RotateKey(ctx, *accountID, keyID, dto.RotateAPIKeyRequest{GracePeriodMinutes: *grace})
+		if appErr != nil {
+			errs = append(errs, fmt.Errorf("key %s: %s", keyID, appErr.Message))
+			continue
+		}
+		fmt.Printf("rotated %s -> %s (%s)\n", keyID, rotated.ID, rotated.Name)
+		fmt.Printf("key: %s\n", rotated.Key)
+	}
+	return errors.Join(errs...)
+}
@@ -1,22 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strings"
+
 	"ride-sharing/config"
 	_ "ride-sharing/docs"
-	userModel "ride-sharing/internal/domains/users/models"
-	"ride-sharing/internal/pkg/auth"
-	"ride-sharing/internal/pkg/database"
-	"ride-sharing/internal/pkg/grpcclient"
-	"ride-sharing/internal/pkg/kafka"
 	"ride-sharing/internal/pkg/logging"
-	"ride-sharing/internal/pkg/redis"
-	"ride-sharing/internal/pkg/validation"
-	"ride-sharing/internal/routes"
-	"time"
-
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 )
 
 // @title           Ride Sharing Auth API
@@ -38,8 +33,37 @@ import (
 // @name                        Authorization
 // @description                 Type "Bearer" followed by a space and JWT token
 func main() {
+	// Global flags come before the command and apply to every command
+	global := flag.NewFlagSet("ride-sharing", flag.ContinueOnError)
+	configFile := global.String("config", "", "YAML config file (defaults to $CONFIG_FILE)")
+	overrides := settingOverrides{}
+	global.Var(overrides, "set", "override a setting by its environment variable name, e.g. -set SERVER_PORT=9090 (repeatable)")
+	global.Usage = printUsage
+	if err := global.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+
+	name, args := "serve", global.Args()
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		os.Exit(2)
+	}
+	if cmd.run == nil {
+		printUsage()
+		return
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Options{File: *configFile, Overrides: overrides})
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
@@ -50,54 +74,66 @@ func main() {
 		ServiceName: cfg.Log.ServiceName,
 	})
 
-	// Initialize database
-	db, err := database.NewPostgresDB(database.DBConfig{
-		Host:     cfg.DB.Host,
-		Port:     cfg.DB.Port,
-		User:     cfg.DB.User,
-		Password: cfg.DB.Password,
-		Name:     cfg.DB.Name,
-	})
-	if err != nil {
-		log.Fatalf("failed to initialize database: %v", err)
+	if err := cmd.run(context.Background(), cfg, args); err != nil {
+		log.Fatalf("%s: %v", name, err)
 	}
+}
 
-	// Initialize Redis
-	redisClient := redis.New(cfg)
-	defer redisClient.Close()
-
-	otpStore := redis.NewOTPStore(redisClient)
-	// Initialize token service
-	tokenService := auth.NewTokenService(
-		cfg.JWT.AccessSecret,
-		cfg.JWT.RefreshSecret,
-		time.Hour*6,    // Access token expires in 1 hour
-		time.Hour*24*7, // Refresh token expires in 1 week
-	)
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, cfg *config.Config, args []string) error
+}
 
-	kafkaProducer := kafka.NewProducerFromAppConfig(cfg)
-	defer kafkaProducer.Close()
+// commands share config.Load and the wiring helpers so maintenance tasks behave like the server
+var commands = []command{
+	{name: "serve", summary: "start the HTTP and gRPC servers (default)", run: runServe},
+	{name: "migrate", summary: "apply, roll back or inspect schema migrations", run: runMigrate},
+	{name: "seed", summary: "create demo accounts for local development", run: runSeed},
+	{name: "create-admin", summary: "create an admin account", run: runCreateAdmin},
+	{name: "reset-password", summary: "set a new password for an account and sign it out everywhere", run: runResetPassword},
+	{name: "revoke-user-sessions", summary: "invalidate every access and refresh token of an account", run: runRevokeUserSessions},
+	{name: "suspend-account", summary: "block an account from signing in and end its sessions", run: runSuspendAccount},
+	{name: "unsuspend-account", summary: "lift the suspension of an account", run: runUnsuspendAccount},
+	{name: "rotate-keys", summary: "rotate service account API keys with a grace period", run: runRotateKeys},
+	{name: "help", summary: "show this help"},
+}
 
-	notificationService, err := grpcclient.NewNotificationClient(cfg, kafkaProducer)
-	if err != nil {
-		log.Fatalf("failed to establish connection with notification server: %v", err)
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
 	}
-	// Auto-migrate models
-	if err := database.AutoMigrate(db, &userModel.User{}); err != nil {
-		log.Fatalf("failed to auto-migrate models: %v", err)
+	return command{}, false
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: ride-sharing [-config file] [-set KEY=VALUE]... <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "settings are layered: defaults, the config file, environment variables")
+	fmt.Fprintln(os.Stderr, "(or KEY_FILE holding the value), then -set overrides")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "run 'ride-sharing <command> -h' for the flags of a command")
+}
 
-	// Setup router
-	router := routes.SetupRouter(db, tokenService, otpStore, notificationService, cfg)
+// settingOverrides collects -set KEY=VALUE flags
+type settingOverrides map[string]string
 
-	// Register custom validators
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		validation.RegisterCustomValidators(v)
-	}
+func (o settingOverrides) String() string {
+	return fmt.Sprint(map[string]string(o))
+}
 
-	// Start server
-	log.Printf("server starting on port %s", cfg.Server.Port)
-	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+func (o settingOverrides) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
 	}
+	o[key] = val
+	return nil
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"ride-sharing/config"
+	"ride-sharing/internal/pkg/database"
+)
+
+const migrateUsage = `usage: ride-sharing migrate <command>
+
+commands:
+  up            apply all pending migrations
+  down [n]      roll back the last n migrations (default 1)
+  to <version>  migrate up or down to the given version, 0 rolls back everything
+  status        list migrations and whether they are applied`
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	var done []int
+	switch args[0] {
+	case "up":
+		done, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		done, err = migrator.Down(ctx, steps)
+	case "to":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		done, err = migrator.To(ctx, version)
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	for _, version := range done {
+		fmt.Printf("migrated %d\n", version)
+	}
+	if err == nil && len(done) == 0 {
+		fmt.Println("nothing to migrate")
+	}
+	return err
+}
+
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		if status.Unknown {
+			state = "unknown"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
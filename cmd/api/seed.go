@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"ride-sharing/config"
+	adminModel "ride-sharing/internal/domains/admin/models"
+	adminRepository "ride-sharing/internal/domains/admin/repository"
+	userModel "ride-sharing/internal/domains/users/models"
+	userRepository "ride-sharing/internal/domains/users/repository"
+	"ride-sharing/internal/pkg/password"
+)
+
+// runSeed creates a demo admin and an active demo user. Existing accounts are left alone,
+// so it is safe to run repeatedly.
+func runSeed(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	pw := flags.String("password", "Demo@Password1", "password for the seeded accounts")
+	flags.Parse(args)
+
+	if cfg.IsProduction() {
+		return errors.New("refusing to seed demo accounts in production")
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	policy, err := setupPasswords(cfg)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(policy, *pw); err != nil {
+		return err
+	}
+	hashed, err := password.HashPassword(*pw)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	admins := adminRepository.NewAdminRepository(db)
+	const adminEmail = "admin@ride-sharing.local"
+	existingAdmin, err := admins.GetByEmail(ctx, adminEmail)
+	if err != nil {
+		return err
+	}
+	if existingAdmin == nil {
+		if err := admins.Create(ctx, &adminModel.Admin{
+			FullName:          "Demo Admin",
+			Email:             adminEmail,
+			Password:          hashed,
+			Active:            true,
+			PasswordChangedAt: &now,
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("seeded admin %s\n", adminEmail)
+	}
+
+	users := userRepository.NewUserRepository(db)
+	const userEmail = "user@ride-sharing.local"
+	existingUser, err := users.GetByEmail(ctx, userEmail)
+	if err != nil {
+		return err
+	}
+	if existingUser == nil {
+		if err := users.Create(ctx, &userModel.User{
+			FullName:          "Demo User",
+			Phone:             "9800000000",
+			Address:           "Kathmandu",
+			Email:             userEmail,
+			Password:          hashed,
+			Active:            true,
+			Locale:            "en",
+			PasswordChangedAt: &now,
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("seeded user %s\n", userEmail)
+	}
+
+	fmt.Println("seed complete")
+	return nil
+}
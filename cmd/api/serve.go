@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ride-sharing/config"
+	notificationRepository "ride-sharing/internal/domains/notifications/repository"
+	notificationDomainService "ride-sharing/internal/domains/notifications/service"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/database"
+	"ride-sharing/internal/pkg/grpcclient"
+	"ride-sharing/internal/pkg/health"
+	"ride-sharing/internal/pkg/kafka"
+	"ride-sharing/internal/pkg/lifecycle"
+	"ride-sharing/internal/pkg/outbox"
+	"ride-sharing/internal/pkg/redis"
+	"ride-sharing/internal/pkg/templates"
+	"ride-sharing/internal/pkg/tracing"
+	"ride-sharing/internal/pkg/validation"
+	"ride-sharing/internal/routes"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// runServe starts the HTTP and gRPC servers together with the background workers and
+// shuts everything down in order on SIGINT or SIGTERM
+func runServe(ctx context.Context, cfg *config.Config, args []string) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// Components register their stop hooks as they are created; see shutdown order in lifecycle.Stage
+	lc := lifecycle.New(lifecycle.Config{
+		DrainTimeout:  time.Duration(cfg.Shutdown.DrainTimeoutMs) * time.Millisecond,
+		WorkerTimeout: time.Duration(cfg.Shutdown.WorkerTimeoutMs) * time.Millisecond,
+		CloseTimeout:  time.Duration(cfg.Shutdown.CloseTimeoutMs) * time.Millisecond,
+	})
+	// Release whatever was opened if startup fails part way
+	started := false
+	defer func() {
+		if !started {
+			lc.Shutdown(context.Background())
+		}
+	}()
+
+	// Registered first so it runs last in the flush stage, after the Kafka writers
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		ServiceName: cfg.Log.ServiceName,
+		Version:     cfg.Log.Version,
+		Environment: cfg.Server.Environment,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		Insecure:    cfg.Tracing.OTLPInsecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+	lc.OnStop(lifecycle.StageFlush, "tracing", shutdownTracing)
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	lc.OnStop(lifecycle.StageClose, "postgres", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+
+	// Initialize Redis
+	redisClient := redis.New(cfg)
+	lc.OnStop(lifecycle.StageClose, "redis", func(ctx context.Context) error { return redisClient.Close() })
+
+	otpStore := redis.NewOTPStore(redisClient, redis.OTPConfig{
+		TTL:         time.Duration(cfg.OTP.TTLSeconds) * time.Second,
+		BoundTTL:    time.Duration(cfg.OTP.LoginTTLSeconds) * time.Second,
+		MaxAttempts: cfg.OTP.MaxAttempts,
+	})
+	rateLimiter := redis.NewRateLimiter(redisClient)
+	pubsub := redis.NewPubSub(redisClient)
+	// Initialize token service
+	tokenService := auth.NewTokenService(
+		cfg.JWT.AccessSecret,
+		cfg.JWT.RefreshSecret,
+		time.Duration(cfg.JWT.AccessTTLMinutes)*time.Minute,
+		time.Duration(cfg.JWT.RefreshTTLHours)*time.Hour,
+	)
+	tokenService.SetImpersonationExpiry(time.Duration(cfg.JWT.ImpersonationTTLMinutes) * time.Minute)
+
+	kafkaProducer := kafka.NewProducerFromAppConfig(cfg)
+	lc.OnStop(lifecycle.StageFlush, "kafka producer", func(ctx context.Context) error { return kafkaProducer.Close() })
+
+	renderer, err := templates.NewRenderer(cfg.Notification.DefaultLocale, cfg.Notification.TemplatesDir)
+	if err != nil {
+		return err
+	}
+
+	notificationService, err := grpcclient.NewNotificationClient(cfg, kafkaProducer, db, renderer)
+	if err != nil {
+		return fmt.Errorf("failed to establish connection with notification server: %w", err)
+	}
+	lc.OnStop(lifecycle.StageClose, "notification client", func(ctx context.Context) error { return notificationService.Close() })
+	// Apply pending schema migrations; the advisory lock keeps concurrent replicas from racing
+	if cfg.DB.MigrateOnStart {
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	passwordPolicy, err := setupPasswords(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Readiness only fails on Postgres or Redis; the outbox buffers events while Kafka is away
+	// and notifications fall back or queue, so those two only degrade the report
+	healthChecker := health.NewChecker(time.Duration(cfg.Server.HealthCheckTimeoutMs) * time.Millisecond)
+	healthChecker.Register("postgres", true, func(ctx context.Context) error { return database.Ping(ctx, db) })
+	healthChecker.Register("redis", true, redisClient.Ping)
+	healthChecker.Register("kafka", false, kafkaProducer.Ping)
+	healthChecker.Register("notification", false, notificationService.Ping)
+
+	// Shared by the inbox API and the retention job
+	inboxService := notificationDomainService.NewInboxService(notificationRepository.NewInboxRepository(db), pubsub)
+
+	// Setup router
+	router := routes.SetupRouter(db, tokenService, otpStore, rateLimiter, inboxService, redisClient, notificationService, renderer, passwordPolicy, healthChecker, cfg)
+
+	// Register custom validators
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := validation.RegisterCustomValidators(v, passwordPolicy); err != nil {
+			return fmt.Errorf("failed to register validators: %w", err)
+		}
+	}
+
+	// Deliver queued notifications in the background when async mode is on
+	if cfg.Notification.Async {
+		deliveryWorker := grpcclient.NewDeliveryWorker(db, notificationService, grpcclient.DeliveryWorkerConfig{
+			PollInterval: time.Duration(cfg.Notification.WorkerPollMs) * time.Millisecond,
+			MaxAttempts:  cfg.Notification.WorkerMaxAttempts,
+		})
+		lc.Go("notification delivery", func(ctx context.Context) error {
+			deliveryWorker.Run(ctx)
+			return nil
+		})
+	}
+
+	// Drop inbox items past the retention window
+	lc.Go("inbox retention", func(ctx context.Context) error {
+		inboxService.RunRetention(ctx, time.Duration(cfg.Notification.InboxRetentionDays)*24*time.Hour)
+		return nil
+	})
+
+	// Relay outbox events to Kafka in the background
+	outboxProducer := kafka.NewOutboxProducerFromAppConfig(cfg)
+	lc.OnStop(lifecycle.StageFlush, "outbox producer", func(ctx context.Context) error { return outboxProducer.Close() })
+	outboxRelay := outbox.NewRelay(db, outboxProducer, outbox.RelayConfig{
+		PollInterval: time.Duration(cfg.Outbox.PollIntervalMs) * time.Millisecond,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Retention:    time.Duration(cfg.Outbox.RetentionHours) * time.Hour,
+		MaxBackoff:   time.Duration(cfg.Outbox.MaxBackoffMs) * time.Millisecond,
+	})
+	lc.Go("outbox relay", func(ctx context.Context) error {
+		outboxRelay.Run(ctx)
+		return nil
+	})
+
+	// Consume events from other services when topics are configured
+	if len(cfg.Kafka.ConsumerTopics) > 0 {
+		consumer := routes.SetupConsumer(cfg, outboxProducer)
+		lc.Go("kafka consumer", consumer.Run)
+	}
+
+	// Start gRPC server alongside the HTTP server
+	grpcServer := routes.SetupGRPCServer(db, tokenService, rateLimiter, redisClient, cfg)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutMs) * time.Millisecond,
+	}
+
+	// Hooks in a stage run in reverse order: readiness fails first, then streams end,
+	// then gRPC and HTTP drain their in-flight calls
+	lc.OnStop(lifecycle.StageServers, "http server", func(ctx context.Context) error {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			httpServer.Close()
+			return err
+		}
+		return nil
+	})
+	lc.OnStop(lifecycle.StageServers, "grpc server", func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			grpcServer.Stop()
+			return ctx.Err()
+		}
+	})
+	lc.OnStop(lifecycle.StageServers, "event streams", func(ctx context.Context) error {
+		pubsub.Close()
+		return nil
+	})
+	lc.OnStop(lifecycle.StageServers, "readiness", func(ctx context.Context) error {
+		healthChecker.Drain()
+		select {
+		case <-time.After(time.Duration(cfg.Shutdown.ReadinessDelayMs) * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
+	serverErr := make(chan error, 2)
+	go func() {
+		log.Printf("gRPC server starting on port %s", cfg.Server.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			serverErr <- fmt.Errorf("gRPC server failed: %w", err)
+		}
+	}()
+	go func() {
+		log.Printf("server starting on port %s", cfg.Server.Port)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- fmt.Errorf("failed to start server: %w", err)
+		}
+	}()
+	started = true
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Printf("shutdown signal received, stopping")
+	case runErr = <-serverErr:
+		log.Printf("%v, stopping", runErr)
+	}
+
+	if err := lc.Shutdown(context.Background()); err != nil {
+		log.Printf("shutdown finished with errors: %v", err)
+	}
+	return runErr
+}
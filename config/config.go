@@ -1,109 +1,402 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	DB struct {
-		Host     string
-		Port     string
-		User     string
-		Password string
-		Name     string
-	}
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port"`
+		User     string `yaml:"user"`
+		Password string `yaml:"password"`
+		Name     string `yaml:"name"`
+		// MigrateOnStart applies pending migrations before serving
+		MigrateOnStart         bool `yaml:"migrate_on_start"`
+		MaxOpenConns           int  `yaml:"max_open_conns"`
+		MaxIdleConns           int  `yaml:"max_idle_conns"`
+		ConnMaxLifetimeMinutes int  `yaml:"conn_max_lifetime_minutes"`
+		ConnectTimeoutMs       int  `yaml:"connect_timeout_ms"`
+	} `yaml:"db"`
 	Redis struct {
-		Host     string
-		Port     string
-		Password string
-		DB       int
-	}
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port"`
+		Password string `yaml:"password"`
+		DB       int    `yaml:"db"`
+	} `yaml:"redis"`
 	Server struct {
-		Port        string
-		Environment string
-		SwaggerURL  string `mapstructure:"SWAGGER_URL"`
-	}
+		Port        string `yaml:"port"`
+		Environment string `yaml:"environment"`
+		SwaggerURL  string `yaml:"swagger_url"`
+		GRPCPort    string `yaml:"grpc_port"`
+		// HealthCheckTimeoutMs bounds each dependency check made by the readiness endpoint
+		HealthCheckTimeoutMs int `yaml:"health_check_timeout_ms"`
+		ReadHeaderTimeoutMs  int `yaml:"read_header_timeout_ms"`
+	} `yaml:"server"`
 	JWT struct {
-		AccessSecret  string
-		RefreshSecret string
-	}
+		AccessSecret  string `yaml:"access_secret"`
+		RefreshSecret string `yaml:"refresh_secret"`
+
+		AccessTTLMinutes        int `yaml:"access_ttl_minutes"`
+		RefreshTTLHours         int `yaml:"refresh_ttl_hours"`
+		ImpersonationTTLMinutes int `yaml:"impersonation_ttl_minutes"`
+	} `yaml:"jwt"`
+	OTP struct {
+		TTLSeconds      int `yaml:"ttl_seconds"`
+		LoginTTLSeconds int `yaml:"login_ttl_seconds"` // device bound passwordless codes
+		MaxAttempts     int `yaml:"max_attempts"`
+	} `yaml:"otp"`
 	Notification struct {
-		Host string
-		Port string
-	}
+		Host              string `yaml:"host"`
+		Port              string `yaml:"port"`
+		CallTimeoutMs     int    `yaml:"call_timeout_ms"`
+		MaxAttempts       int    `yaml:"max_attempts"`
+		BreakerThreshold  int    `yaml:"breaker_threshold"`
+		BreakerCooldownMs int    `yaml:"breaker_cooldown_ms"`
+		Async             bool   `yaml:"async"`
+		WorkerPollMs      int    `yaml:"worker_poll_ms"`
+		WorkerMaxAttempts int    `yaml:"worker_max_attempts"`
+
+		InboxRetentionDays int    `yaml:"inbox_retention_days"`
+		DefaultLocale      string `yaml:"default_locale"`
+		TemplatesDir       string `yaml:"templates_dir"` // overrides the embedded templates when set
+	} `yaml:"notification"`
 	Kafka struct {
-		Brokers  []string
-		Topic    string
-		Balancer string
-	}
+		Brokers  []string `yaml:"brokers"`
+		Topic    string   `yaml:"topic"`
+		Balancer string   `yaml:"balancer"`
+
+		ConsumerGroup          string   `yaml:"consumer_group"`
+		ConsumerTopics         []string `yaml:"consumer_topics"`
+		ConsumerConcurrency    int      `yaml:"consumer_concurrency"`
+		ConsumerMaxAttempts    int      `yaml:"consumer_max_attempts"`
+		ConsumerRetryBackoffMs int      `yaml:"consumer_retry_backoff_ms"`
+	} `yaml:"kafka"`
+	Outbox struct {
+		PollIntervalMs int `yaml:"poll_interval_ms"`
+		BatchSize      int `yaml:"batch_size"`
+		RetentionHours int `yaml:"retention_hours"`
+		MaxBackoffMs   int `yaml:"max_backoff_ms"`
+	} `yaml:"outbox"`
+	Shutdown struct {
+		// ReadinessDelayMs keeps serving after readiness starts failing so load balancers can catch up
+		ReadinessDelayMs int `yaml:"readiness_delay_ms"`
+		DrainTimeoutMs   int `yaml:"drain_timeout_ms"`
+		WorkerTimeoutMs  int `yaml:"worker_timeout_ms"`
+		CloseTimeoutMs   int `yaml:"close_timeout_ms"`
+	} `yaml:"shutdown"`
+	Idempotency struct {
+		LockTimeoutSeconds int `yaml:"lock_timeout_seconds"`
+		RetentionHours     int `yaml:"retention_hours"`
+	} `yaml:"idempotency"`
+	FeatureFlags struct {
+		// CacheTTLSeconds is how long other replicas may serve a flag after it changes
+		CacheTTLSeconds int `yaml:"cache_ttl_seconds"`
+	} `yaml:"feature_flags"`
+	IdentityCache struct {
+		// TTLSeconds bounds how long a stale identity can be served if an invalidation is missed
+		TTLSeconds int `yaml:"ttl_seconds"`
+	} `yaml:"identity_cache"`
+	Tracing struct {
+		Exporter     string  `yaml:"exporter"` // none, otlp or stdout
+		OTLPEndpoint string  `yaml:"otlp_endpoint"`
+		OTLPInsecure bool    `yaml:"otlp_insecure"`
+		SampleRatio  float64 `yaml:"sample_ratio"`
+	} `yaml:"tracing"`
 	Log struct {
-		Environment string
-		Version     string
-		ServiceName string
-	}
+		Environment string `yaml:"environment"`
+		Version     string `yaml:"version"`
+		ServiceName string `yaml:"service_name"`
+	} `yaml:"log"`
+	Password struct {
+		MinLength      int    `yaml:"min_length"`
+		RequireUpper   bool   `yaml:"require_upper"`
+		RequireLower   bool   `yaml:"require_lower"`
+		RequireDigit   bool   `yaml:"require_digit"`
+		RequireSpecial bool   `yaml:"require_special"`
+		MaxAgeDays     int    `yaml:"max_age_days"`
+		HistorySize    int    `yaml:"history_size"`
+		BreachListFile string `yaml:"breach_list_file"`
+		HashAlgorithm  string `yaml:"hash_algorithm"`
+		Argon2Memory   int    `yaml:"argon2_memory_kb"`
+		Argon2Time     int    `yaml:"argon2_time"`
+		Argon2Threads  int    `yaml:"argon2_threads"`
+		BcryptCost     int    `yaml:"bcrypt_cost"`
+	} `yaml:"password"`
+}
+
+// Options selects the optional configuration layers
+type Options struct {
+	// File is a YAML config file; CONFIG_FILE is used when empty
+	File string
+	// Overrides take precedence over everything else, keyed by environment variable name
+	Overrides map[string]string
 }
 
-func Load() (*Config, error) {
+// Load builds the configuration from, in increasing order of precedence: the defaults,
+// the YAML file, environment variables (or KEY_FILE for secrets mounted as files) and
+// the overrides. The result is validated before it is returned.
+func Load(opts Options) (*Config, error) {
 	// Load .env file
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("Warning: No .env file found - using system environment variables")
 	}
 
+	cfg := Defaults()
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		if err := loadFile(cfg, file); err != nil {
+			return nil, err
+		}
+	}
+
+	src := &source{overrides: opts.Overrides}
+	applyEnv(cfg, src)
+	if err := errors.Join(src.errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	// Logs follow the server environment unless the file sets log.environment
+	if cfg.Log.Environment == "" {
+		cfg.Log.Environment = cfg.Server.Environment
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Defaults returns the built-in configuration, suitable for local development only
+func Defaults() *Config {
 	cfg := &Config{}
 
+	cfg.DB.Host = "localhost"
+	cfg.DB.Port = "5432"
+	cfg.DB.User = "postgres"
+	cfg.DB.Password = "postgres"
+	cfg.DB.Name = "ride-sharing"
+	cfg.DB.MigrateOnStart = true
+	cfg.DB.MaxOpenConns = 100
+	cfg.DB.MaxIdleConns = 10
+	cfg.DB.ConnMaxLifetimeMinutes = 60
+	cfg.DB.ConnectTimeoutMs = 5000
+
+	cfg.Redis.Host = "localhost"
+	cfg.Redis.Port = "6379"
+
+	cfg.Server.Port = "8080"
+	cfg.Server.Environment = "Dev"
+	cfg.Server.GRPCPort = "50052"
+	cfg.Server.HealthCheckTimeoutMs = 2000
+	cfg.Server.ReadHeaderTimeoutMs = 10000
+
+	cfg.JWT.AccessSecret = DefaultSecret
+	cfg.JWT.RefreshSecret = DefaultSecret
+	cfg.JWT.AccessTTLMinutes = 6 * 60
+	cfg.JWT.RefreshTTLHours = 7 * 24
+	cfg.JWT.ImpersonationTTLMinutes = 15
+
+	cfg.OTP.TTLSeconds = 120
+	cfg.OTP.LoginTTLSeconds = 300
+	cfg.OTP.MaxAttempts = 5
+
+	cfg.Log.Version = "1.0.0"
+	cfg.Log.ServiceName = "auth-service"
+
+	cfg.Idempotency.LockTimeoutSeconds = 30
+	cfg.Idempotency.RetentionHours = 24
+
+	cfg.FeatureFlags.CacheTTLSeconds = 30
+
+	cfg.IdentityCache.TTLSeconds = 60
+
+	cfg.Tracing.Exporter = "none"
+	cfg.Tracing.OTLPEndpoint = "localhost:4317"
+	cfg.Tracing.OTLPInsecure = true
+	cfg.Tracing.SampleRatio = 1
+
+	cfg.Notification.Host = "localhost"
+	cfg.Notification.Port = "50051"
+	cfg.Notification.CallTimeoutMs = 2000
+	cfg.Notification.MaxAttempts = 3
+	cfg.Notification.BreakerThreshold = 5
+	cfg.Notification.BreakerCooldownMs = 30000
+	cfg.Notification.Async = true
+	cfg.Notification.WorkerPollMs = 1000
+	cfg.Notification.WorkerMaxAttempts = 8
+	cfg.Notification.InboxRetentionDays = 90
+	cfg.Notification.DefaultLocale = "en"
+
+	cfg.Kafka.Brokers = []string{"localhost:9092"}
+	cfg.Kafka.Topic = "default-topic"
+	cfg.Kafka.Balancer = "least-bytes"
+	cfg.Kafka.ConsumerGroup = "ride-sharing"
+	cfg.Kafka.ConsumerConcurrency = 4
+	cfg.Kafka.ConsumerMaxAttempts = 5
+	cfg.Kafka.ConsumerRetryBackoffMs = 1000
+
+	cfg.Outbox.PollIntervalMs = 1000
+	cfg.Outbox.BatchSize = 100
+	cfg.Outbox.RetentionHours = 168
+	cfg.Outbox.MaxBackoffMs = 5 * 60 * 1000
+
+	cfg.Shutdown.DrainTimeoutMs = 20000
+	cfg.Shutdown.WorkerTimeoutMs = 10000
+	cfg.Shutdown.CloseTimeoutMs = 5000
+
+	cfg.Password.MinLength = 8
+	cfg.Password.RequireUpper = true
+	cfg.Password.RequireLower = true
+	cfg.Password.RequireDigit = true
+	cfg.Password.RequireSpecial = true
+	cfg.Password.HistorySize = 5
+	cfg.Password.HashAlgorithm = "argon2id"
+	cfg.Password.Argon2Memory = 64 * 1024
+	cfg.Password.Argon2Time = 3
+	cfg.Password.Argon2Threads = 2
+	cfg.Password.BcryptCost = 10
+	return cfg
+}
+
+// IsProduction reports whether the service runs in the production environment
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.Server.Environment, "production")
+}
+
+// loadFile overlays the YAML file on cfg. Unknown keys are rejected so typos do not go unnoticed.
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config, src *source) {
 	// Database configuration
-	cfg.DB.Host = getEnv("DB_HOST", "localhost")
-	cfg.DB.Port = getEnv("DB_PORT", "5432")
-	cfg.DB.User = getEnv("POSTGRES_USER", "postgres")
-	cfg.DB.Password = getEnv("POSTGRES_PASSWORD", "postgres")
-	cfg.DB.Name = getEnv("POSTGRES_DB", "ride-sharing")
+	src.str(&cfg.DB.Host, "DB_HOST")
+	src.str(&cfg.DB.Port, "DB_PORT")
+	src.str(&cfg.DB.User, "POSTGRES_USER")
+	src.str(&cfg.DB.Password, "POSTGRES_PASSWORD")
+	src.str(&cfg.DB.Name, "POSTGRES_DB")
+	src.boolean(&cfg.DB.MigrateOnStart, "DB_MIGRATE_ON_START")
+	src.integer(&cfg.DB.MaxOpenConns, "DB_MAX_OPEN_CONNS")
+	src.integer(&cfg.DB.MaxIdleConns, "DB_MAX_IDLE_CONNS")
+	src.integer(&cfg.DB.ConnMaxLifetimeMinutes, "DB_CONN_MAX_LIFETIME_MINUTES")
+	src.integer(&cfg.DB.ConnectTimeoutMs, "DB_CONNECT_TIMEOUT_MS")
 
 	// Redis configuration
-	cfg.Redis.Host = getEnv("REDIS_HOST", "localhost")
-	cfg.Redis.Port = getEnv("REDIS_PORT", "6379")
-	cfg.Redis.Password = getEnv("REDIS_PASSWORD", "")
-	cfg.Redis.DB = getEnvAsInt("REDIS_DB", 0)
+	src.str(&cfg.Redis.Host, "REDIS_HOST")
+	src.str(&cfg.Redis.Port, "REDIS_PORT")
+	src.str(&cfg.Redis.Password, "REDIS_PASSWORD")
+	src.integer(&cfg.Redis.DB, "REDIS_DB")
 
 	// Server configuration
-	cfg.Server.Port = getEnv("SERVER_PORT", "8080")
-	cfg.Server.Environment = getEnv("ENVIRONMENT", "Dev")
+	src.str(&cfg.Server.Port, "SERVER_PORT")
+	src.str(&cfg.Server.Environment, "ENVIRONMENT")
+	src.str(&cfg.Server.SwaggerURL, "SWAGGER_URL")
+	src.str(&cfg.Server.GRPCPort, "GRPC_PORT")
+	src.integer(&cfg.Server.HealthCheckTimeoutMs, "HEALTH_CHECK_TIMEOUT_MS")
+	src.integer(&cfg.Server.ReadHeaderTimeoutMs, "SERVER_READ_HEADER_TIMEOUT_MS")
 
 	// JWT configuration
-	cfg.JWT.AccessSecret = getEnv("ACCESS_TOKEN_SECRET", "default-secret-key")
-	cfg.JWT.RefreshSecret = getEnv("REFRESH_TOKEN_SECRET", "default-secret-key")
+	src.str(&cfg.JWT.AccessSecret, "ACCESS_TOKEN_SECRET")
+	src.str(&cfg.JWT.RefreshSecret, "REFRESH_TOKEN_SECRET")
+	src.integer(&cfg.JWT.AccessTTLMinutes, "ACCESS_TOKEN_TTL_MINUTES")
+	src.integer(&cfg.JWT.RefreshTTLHours, "REFRESH_TOKEN_TTL_HOURS")
+	src.integer(&cfg.JWT.ImpersonationTTLMinutes, "IMPERSONATION_TOKEN_TTL_MINUTES")
+
+	// One-time passwords
+	src.integer(&cfg.OTP.TTLSeconds, "OTP_TTL_SECONDS")
+	src.integer(&cfg.OTP.LoginTTLSeconds, "OTP_LOGIN_TTL_SECONDS")
+	src.integer(&cfg.OTP.MaxAttempts, "OTP_MAX_ATTEMPTS")
+
+	src.str(&cfg.Log.Version, "VERSION")
+	src.str(&cfg.Log.ServiceName, "SERVICE_NAME")
+
+	// Idempotency keys
+	src.integer(&cfg.Idempotency.LockTimeoutSeconds, "IDEMPOTENCY_LOCK_TIMEOUT_SECONDS")
+	src.integer(&cfg.Idempotency.RetentionHours, "IDEMPOTENCY_RETENTION_HOURS")
+
+	// Feature flags
+	src.integer(&cfg.FeatureFlags.CacheTTLSeconds, "FEATURE_FLAG_CACHE_TTL_SECONDS")
+
+	// Identity cache
+	src.integer(&cfg.IdentityCache.TTLSeconds, "IDENTITY_CACHE_TTL_SECONDS")
 
-	cfg.Log.Environment = getEnv("ENVIRONMENT", "Dev")
-	cfg.Log.Version = getEnv("VERSION", "1.0.0")
-	cfg.Log.ServiceName = getEnv("SERVICE_NAME", "auth-service")
+	// Tracing
+	src.str(&cfg.Tracing.Exporter, "TRACING_EXPORTER")
+	src.str(&cfg.Tracing.OTLPEndpoint, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	src.boolean(&cfg.Tracing.OTLPInsecure, "OTEL_EXPORTER_OTLP_INSECURE")
+	src.float(&cfg.Tracing.SampleRatio, "TRACING_SAMPLE_RATIO")
 
 	// Notification server config
-	cfg.Notification.Host = getEnv("NOTIFICATION_HOST", "localhost")
-	cfg.Notification.Port = getEnv("NOTIFICATION_PORT", "50051")
+	src.str(&cfg.Notification.Host, "NOTIFICATION_HOST")
+	src.str(&cfg.Notification.Port, "NOTIFICATION_PORT")
+	src.integer(&cfg.Notification.CallTimeoutMs, "NOTIFICATION_CALL_TIMEOUT_MS")
+	src.integer(&cfg.Notification.MaxAttempts, "NOTIFICATION_MAX_ATTEMPTS")
+	src.integer(&cfg.Notification.BreakerThreshold, "NOTIFICATION_BREAKER_THRESHOLD")
+	src.integer(&cfg.Notification.BreakerCooldownMs, "NOTIFICATION_BREAKER_COOLDOWN_MS")
+	src.boolean(&cfg.Notification.Async, "NOTIFICATION_ASYNC")
+	src.integer(&cfg.Notification.WorkerPollMs, "NOTIFICATION_WORKER_POLL_MS")
+	src.integer(&cfg.Notification.WorkerMaxAttempts, "NOTIFICATION_WORKER_MAX_ATTEMPTS")
+	src.integer(&cfg.Notification.InboxRetentionDays, "INBOX_RETENTION_DAYS")
+	src.str(&cfg.Notification.DefaultLocale, "DEFAULT_LOCALE")
+	src.str(&cfg.Notification.TemplatesDir, "NOTIFICATION_TEMPLATES_DIR")
 
-	// fallback kafka server config
-	cfg.Kafka.Brokers = []string{getEnv("KAFKA_BROKER", "localhost:9092")}
-	cfg.Kafka.Topic = getEnv("KAFKA_TOPIC", "default-topic")
-	cfg.Kafka.Balancer = getEnv("KAFKA_BALANCER", "least-bytes")
-	return cfg, nil
-}
+	// Kafka; KAFKA_BROKER takes a comma separated list
+	src.list(&cfg.Kafka.Brokers, "KAFKA_BROKER")
+	src.str(&cfg.Kafka.Topic, "KAFKA_TOPIC")
+	src.str(&cfg.Kafka.Balancer, "KAFKA_BALANCER")
+	src.str(&cfg.Kafka.ConsumerGroup, "KAFKA_CONSUMER_GROUP")
+	src.list(&cfg.Kafka.ConsumerTopics, "KAFKA_CONSUMER_TOPICS")
+	src.integer(&cfg.Kafka.ConsumerConcurrency, "KAFKA_CONSUMER_CONCURRENCY")
+	src.integer(&cfg.Kafka.ConsumerMaxAttempts, "KAFKA_CONSUMER_MAX_ATTEMPTS")
+	src.integer(&cfg.Kafka.ConsumerRetryBackoffMs, "KAFKA_CONSUMER_RETRY_BACKOFF_MS")
 
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
-}
+	// Outbox relay
+	src.integer(&cfg.Outbox.PollIntervalMs, "OUTBOX_POLL_INTERVAL_MS")
+	src.integer(&cfg.Outbox.BatchSize, "OUTBOX_BATCH_SIZE")
+	src.integer(&cfg.Outbox.RetentionHours, "OUTBOX_RETENTION_HOURS")
+	src.integer(&cfg.Outbox.MaxBackoffMs, "OUTBOX_MAX_BACKOFF_MS")
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
+	// Graceful shutdown
+	src.integer(&cfg.Shutdown.ReadinessDelayMs, "SHUTDOWN_READINESS_DELAY_MS")
+	src.integer(&cfg.Shutdown.DrainTimeoutMs, "SHUTDOWN_DRAIN_TIMEOUT_MS")
+	src.integer(&cfg.Shutdown.WorkerTimeoutMs, "SHUTDOWN_WORKER_TIMEOUT_MS")
+	src.integer(&cfg.Shutdown.CloseTimeoutMs, "SHUTDOWN_CLOSE_TIMEOUT_MS")
+
+	// Password policy
+	src.integer(&cfg.Password.MinLength, "PASSWORD_MIN_LENGTH")
+	src.boolean(&cfg.Password.RequireUpper, "PASSWORD_REQUIRE_UPPER")
+	src.boolean(&cfg.Password.RequireLower, "PASSWORD_REQUIRE_LOWER")
+	src.boolean(&cfg.Password.RequireDigit, "PASSWORD_REQUIRE_DIGIT")
+	src.boolean(&cfg.Password.RequireSpecial, "PASSWORD_REQUIRE_SPECIAL")
+	src.integer(&cfg.Password.MaxAgeDays, "PASSWORD_MAX_AGE_DAYS")
+	src.integer(&cfg.Password.HistorySize, "PASSWORD_HISTORY_SIZE")
+	src.str(&cfg.Password.BreachListFile, "PASSWORD_BREACH_LIST_FILE")
+	src.str(&cfg.Password.HashAlgorithm, "PASSWORD_HASH_ALGORITHM")
+	src.integer(&cfg.Password.Argon2Memory, "ARGON2_MEMORY_KB")
+	src.integer(&cfg.Password.Argon2Time, "ARGON2_TIME")
+	src.integer(&cfg.Password.Argon2Threads, "ARGON2_THREADS")
+	src.integer(&cfg.Password.BcryptCost, "BCRYPT_COST")
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// source reads values from the overrides and the environment. Any key can instead be
+// given as KEY_FILE naming a file that holds the value, which is how secrets mounted by
+// Docker or Kubernetes are read. Malformed values are collected rather than ignored.
+type source struct {
+	overrides map[string]string
+	errs      []error
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if value, ok := s.overrides[key]; ok {
+		return value, true
+	}
+
+	value, exists := os.LookupEnv(key)
+	path, fromFile := os.LookupEnv(key + "_FILE")
+	if exists && fromFile {
+		s.errs = append(s.errs, fmt.Errorf("%s and %s_FILE are both set", key, key))
+		return "", false
+	}
+	if exists {
+		return value, true
+	}
+	if fromFile {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			s.errs = append(s.errs, fmt.Errorf("%s_FILE: %w", key, err))
+			return "", false
+		}
+		return strings.TrimRight(string(content), "\r\n"), true
+	}
+	return "", false
+}
+
+func (s *source) str(dst *string, key string) {
+	if value, ok := s.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (s *source) integer(dst *int, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	intValue, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+		return
+	}
+	*dst = intValue
+}
+
+func (s *source) boolean(dst *bool, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	boolValue, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a boolean", key, value))
+		return
+	}
+	*dst = boolValue
+}
+
+func (s *source) float(dst *float64, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+	floatValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a number", key, value))
+		return
+	}
+	*dst = floatValue
+}
+
+// list reads a comma separated value, dropping empty items
+func (s *source) list(dst *[]string, key string) {
+	value, ok := s.lookup(key)
+	if !ok {
+		return
+	}
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	*dst = values
+}
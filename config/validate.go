@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// DefaultSecret is the development JWT secret; it is refused in production
+const DefaultSecret = "default-secret-key"
+
+// minProductionSecretLength is 256 bits of HS256 key material
+const minProductionSecretLength = 32
+
+// Validate checks ranges and required values, and refuses insecure defaults in production
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.port("DB_PORT", c.DB.Port)
+	v.port("REDIS_PORT", c.Redis.Port)
+	v.port("SERVER_PORT", c.Server.Port)
+	v.port("GRPC_PORT", c.Server.GRPCPort)
+	v.port("NOTIFICATION_PORT", c.Notification.Port)
+
+	v.required("DB_HOST", c.DB.Host)
+	v.required("POSTGRES_USER", c.DB.User)
+	v.required("POSTGRES_DB", c.DB.Name)
+	v.required("REDIS_HOST", c.Redis.Host)
+	v.required("NOTIFICATION_HOST", c.Notification.Host)
+	v.required("ACCESS_TOKEN_SECRET", c.JWT.AccessSecret)
+	v.required("REFRESH_TOKEN_SECRET", c.JWT.RefreshSecret)
+	v.required("KAFKA_TOPIC", c.Kafka.Topic)
+	if len(c.Kafka.Brokers) == 0 {
+		v.fail("KAFKA_BROKER must list at least one broker")
+	}
+
+	v.positive("DB_MAX_OPEN_CONNS", c.DB.MaxOpenConns)
+	v.nonNegative("DB_MAX_IDLE_CONNS", c.DB.MaxIdleConns)
+	if c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		v.fail("DB_MAX_IDLE_CONNS cannot exceed DB_MAX_OPEN_CONNS")
+	}
+	v.positive("DB_CONN_MAX_LIFETIME_MINUTES", c.DB.ConnMaxLifetimeMinutes)
+	v.positive("DB_CONNECT_TIMEOUT_MS", c.DB.ConnectTimeoutMs)
+	v.nonNegative("REDIS_DB", c.Redis.DB)
+	v.positive("HEALTH_CHECK_TIMEOUT_MS", c.Server.HealthCheckTimeoutMs)
+	v.positive("SERVER_READ_HEADER_TIMEOUT_MS", c.Server.ReadHeaderTimeoutMs)
+
+	v.positive("ACCESS_TOKEN_TTL_MINUTES", c.JWT.AccessTTLMinutes)
+	v.positive("REFRESH_TOKEN_TTL_HOURS", c.JWT.RefreshTTLHours)
+	v.positive("IMPERSONATION_TOKEN_TTL_MINUTES", c.JWT.ImpersonationTTLMinutes)
+	v.positive("OTP_TTL_SECONDS", c.OTP.TTLSeconds)
+	v.positive("OTP_LOGIN_TTL_SECONDS", c.OTP.LoginTTLSeconds)
+	v.positive("OTP_MAX_ATTEMPTS", c.OTP.MaxAttempts)
+
+	v.positive("NOTIFICATION_CALL_TIMEOUT_MS", c.Notification.CallTimeoutMs)
+	v.positive("NOTIFICATION_MAX_ATTEMPTS", c.Notification.MaxAttempts)
+	v.positive("NOTIFICATION_BREAKER_THRESHOLD", c.Notification.BreakerThreshold)
+	v.positive("NOTIFICATION_BREAKER_COOLDOWN_MS", c.Notification.BreakerCooldownMs)
+	v.positive("NOTIFICATION_WORKER_POLL_MS", c.Notification.WorkerPollMs)
+	v.positive("NOTIFICATION_WORKER_MAX_ATTEMPTS", c.Notification.WorkerMaxAttempts)
+	v.positive("INBOX_RETENTION_DAYS", c.Notification.InboxRetentionDays)
+	v.required("DEFAULT_LOCALE", c.Notification.DefaultLocale)
+
+	v.oneOf("KAFKA_BALANCER", c.Kafka.Balancer, "least-bytes", "round-robin", "hash")
+	v.positive("KAFKA_CONSUMER_CONCURRENCY", c.Kafka.ConsumerConcurrency)
+	v.positive("KAFKA_CONSUMER_MAX_ATTEMPTS", c.Kafka.ConsumerMaxAttempts)
+	v.positive("KAFKA_CONSUMER_RETRY_BACKOFF_MS", c.Kafka.ConsumerRetryBackoffMs)
+	if len(c.Kafka.ConsumerTopics) > 0 {
+		v.required("KAFKA_CONSUMER_GROUP", c.Kafka.ConsumerGroup)
+	}
+
+	v.positive("OUTBOX_POLL_INTERVAL_MS", c.Outbox.PollIntervalMs)
+	v.positive("OUTBOX_BATCH_SIZE", c.Outbox.BatchSize)
+	v.positive("OUTBOX_RETENTION_HOURS", c.Outbox.RetentionHours)
+	v.positive("OUTBOX_MAX_BACKOFF_MS", c.Outbox.MaxBackoffMs)
+
+	v.nonNegative("SHUTDOWN_READINESS_DELAY_MS", c.Shutdown.ReadinessDelayMs)
+	v.positive("SHUTDOWN_DRAIN_TIMEOUT_MS", c.Shutdown.DrainTimeoutMs)
+	v.positive("SHUTDOWN_WORKER_TIMEOUT_MS", c.Shutdown.WorkerTimeoutMs)
+	v.positive("SHUTDOWN_CLOSE_TIMEOUT_MS", c.Shutdown.CloseTimeoutMs)
+
+	v.positive("IDEMPOTENCY_LOCK_TIMEOUT_SECONDS", c.Idempotency.LockTimeoutSeconds)
+	v.positive("IDEMPOTENCY_RETENTION_HOURS", c.Idempotency.RetentionHours)
+	v.positive("FEATURE_FLAG_CACHE_TTL_SECONDS", c.FeatureFlags.CacheTTLSeconds)
+	v.positive("IDENTITY_CACHE_TTL_SECONDS", c.IdentityCache.TTLSeconds)
+
+	v.oneOf("TRACING_EXPORTER", c.Tracing.Exporter, "none", "otlp", "stdout")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.Tracing.Exporter == "otlp" {
+		v.required("OTEL_EXPORTER_OTLP_ENDPOINT", c.Tracing.OTLPEndpoint)
+	}
+
+	v.positive("PASSWORD_MIN_LENGTH", c.Password.MinLength)
+	v.nonNegative("PASSWORD_MAX_AGE_DAYS", c.Password.MaxAgeDays)
+	v.nonNegative("PASSWORD_HISTORY_SIZE", c.Password.HistorySize)
+	v.oneOf("PASSWORD_HASH_ALGORITHM", c.Password.HashAlgorithm, "argon2id", "bcrypt")
+	v.positive("ARGON2_MEMORY_KB", c.Password.Argon2Memory)
+	v.positive("ARGON2_TIME", c.Password.Argon2Time)
+	if c.Password.Argon2Threads < 1 || c.Password.Argon2Threads > 255 {
+		v.fail("ARGON2_THREADS must be between 1 and 255")
+	}
+	if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+		v.fail("BCRYPT_COST must be between 4 and 31")
+	}
+
+	if c.IsProduction() {
+		c.validateProduction(v)
+	}
+
+	if err := errors.Join(v.errs...); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+// validateProduction refuses development defaults that would be unsafe in production
+func (c *Config) validateProduction(v *validator) {
+	for _, secret := range []struct{ key, value string }{
+		{"ACCESS_TOKEN_SECRET", c.JWT.AccessSecret},
+		{"REFRESH_TOKEN_SECRET", c.JWT.RefreshSecret},
+	} {
+		if secret.value == DefaultSecret {
+			v.fail(secret.key + " must be changed from the default in production")
+		} else if len(secret.value) < minProductionSecretLength {
+			v.fail(fmt.Sprintf("%s must be at least %d characters in production", secret.key, minProductionSecretLength))
+		}
+	}
+	if c.JWT.AccessSecret == c.JWT.RefreshSecret {
+		v.fail("ACCESS_TOKEN_SECRET and REFRESH_TOKEN_SECRET must differ in production")
+	}
+	if c.DB.Password == "" || c.DB.Password == "postgres" {
+		v.fail("POSTGRES_PASSWORD must be set to a non-default value in production")
+	}
+	if c.Password.HashAlgorithm == "bcrypt" && c.Password.BcryptCost < 10 {
+		v.fail("BCRYPT_COST must be at least 10 in production")
+	}
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(msg string) {
+	v.errs = append(v.errs, errors.New(msg))
+}
+
+func (v *validator) required(key, value string) {
+	if value == "" {
+		v.fail(key + " is required")
+	}
+}
+
+func (v *validator) positive(key string, value int) {
+	if value <= 0 {
+		v.fail(fmt.Sprintf("%s must be positive, got %d", key, value))
+	}
+}
+
+func (v *validator) nonNegative(key string, value int) {
+	if value < 0 {
+		v.fail(fmt.Sprintf("%s cannot be negative, got %d", key, value))
+	}
+}
+
+func (v *validator) port(key, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		v.fail(fmt.Sprintf("%s must be a port number, got %q", key, value))
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, option := range allowed {
+		if value == option {
+			return
+		}
+	}
+	v.fail(fmt.Sprintf("%s must be one of %v, got %q", key, allowed, value))
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+
+	"ride-sharing/internal/domains/admin/dto"
+	"ride-sharing/internal/domains/admin/service"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	service *service.AdminService
+}
+
+func NewAdminHandler(service *service.AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// Admin Login godoc
+// @Summary      Login an admin
+// @Description  Authenticate admin and return access & refresh tokens
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.AdminLoginRequest  true  "Admin login credentials"
+// @Success      200      {object}  response.SuccessResponse{data=dto.AdminLoginResponse}  "Login successful"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Invalid credentials"
+// @Failure      403      {object}  response.ErrorResponse  "Account suspended"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/login [post]
+func (h *AdminHandler) Login(c *gin.Context) {
+	var req dto.AdminLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.Login(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "login successful", res, nil)
+}
+
+// Impersonate godoc
+// @Summary      Impersonate a user
+// @Description  Mint a short-lived access token to act as a user. Sensitive operations are blocked and every request is audited.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.ImpersonateRequest  true  "Impersonation target"
+// @Success      201      {object}  response.SuccessResponse{data=dto.ImpersonationResponse}  "Impersonation token issued"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404      {object}  response.ErrorResponse  "User not found"
+// @Failure      409      {object}  response.ErrorResponse  "Target cannot be impersonated or is suspended"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/impersonate [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, errors.NewUnauthorizedError("user ID not found in context"))
+		return
+	}
+
+	res, err := h.service.Impersonate(c.Request.Context(), adminID.(string), req, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "impersonation token issued", res, nil)
+}
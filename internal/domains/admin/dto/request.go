@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdminLoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type AdminResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email"`
+	FullName string    `json:"full_name"`
+}
+
+type AdminLoginResponse struct {
+	AccessToken  string        `json:"access_token"`
+	RefreshToken string        `json:"refresh_token"`
+	Admin        AdminResponse `json:"admin"`
+}
+
+type ImpersonateRequest struct {
+	UserID   string `json:"user_id" binding:"required,uuid"`
+	UserType string `json:"user_type" binding:"required,oneof=user rider"`
+	Reason   string `json:"reason" binding:"required,max=500"`
+}
+
+type ImpersonationResponse struct {
+	AccessToken    string    `json:"access_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	UserID         string    `json:"user_id"`
+	UserType       string    `json:"user_type"`
+	ImpersonatorID string    `json:"impersonator_id"`
+}
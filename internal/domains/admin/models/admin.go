@@ -0,0 +1,29 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models" // Import the common model package
+	"time"
+)
+
+type Admin struct {
+	CommonModels.Common `swaggerignore:"true"`
+	FullName            string `gorm:"not null"`
+	Email               string `gorm:"unique;not null"`
+	Password            string `gorm:"not null"`
+	Active              bool   `gorm:"default:true"`
+	PasswordChangedAt   *time.Time
+	// SuspendedAt is set while the account is suspended
+	SuspendedAt *time.Time
+}
+
+func (Admin) TableName() string {
+	return "admins"
+}
+
+func (a *Admin) GetPasswordChangedAt() *time.Time {
+	return a.PasswordChangedAt
+}
+
+func (a *Admin) GetSuspendedAt() *time.Time {
+	return a.SuspendedAt
+}
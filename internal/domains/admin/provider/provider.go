@@ -1,20 +1,24 @@
 package provider
 
-// import (
-// 	"context"
+import (
+	"context"
+	"fmt"
 
-// 	"ride-sharing/internal/domains/users/repository"
-// 	"ride-sharing/internal/pkg/auth"
-// )
+	"ride-sharing/internal/domains/admin/repository"
+	"ride-sharing/internal/pkg/auth"
+)
 
-// type UserProvider struct {
-// 	repo repository.UserRepository
-// }
+type AdminProvider struct {
+	repo repository.AdminRepository
+}
 
-// func NewUserProvider(repo repository.UserRepository) auth.UserProvider {
-// 	return &UserProvider{repo: repo}
-// }
+func NewAdminProvider(repo repository.AdminRepository) auth.UserProvider {
+	return &AdminProvider{repo: repo}
+}
 
-// func (p *UserProvider) GetByID(ctx context.Context, id string) (interface{}, error) {
-// 	return p.repo.GetByID(ctx, id)
-// }
+func (p *AdminProvider) GetByID(ctx context.Context, id string, userType auth.UserType) (interface{}, error) {
+	if userType != auth.UserTypeAdmin {
+		return nil, fmt.Errorf("invalid user type: %s", userType)
+	}
+	return p.repo.GetByID(ctx, id)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"ride-sharing/internal/domains/admin/models"
+	customErrors "ride-sharing/internal/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AdminRepository interface {
+	Create(ctx context.Context, admin *models.Admin) error
+	GetByEmail(ctx context.Context, email string) (*models.Admin, error)
+	GetByID(ctx context.Context, id string) (*models.Admin, error)
+	ChangePassword(ctx context.Context, admin *models.Admin, hashedPassword string) error
+	RevokeSessions(ctx context.Context, admin *models.Admin) error
+	SetSuspended(ctx context.Context, admin *models.Admin, suspended bool) error
+}
+
+type adminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) AdminRepository {
+	return &adminRepository{db: db}
+}
+
+func (r *adminRepository) Create(ctx context.Context, admin *models.Admin) error {
+	return r.db.WithContext(ctx).Create(admin).Error
+}
+
+func (r *adminRepository) GetByEmail(ctx context.Context, email string) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&admin).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &admin, nil
+}
+
+func (r *adminRepository) GetByID(ctx context.Context, id string) (*models.Admin, error) {
+	var admin models.Admin
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&admin).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.NewNotFoundError("admin not found")
+		}
+		return nil, customErrors.NewInternalError(err)
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepository) ChangePassword(ctx context.Context, admin *models.Admin, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(admin).Updates(map[string]interface{}{
+		"password":            hashedPassword,
+		"password_changed_at": time.Now(),
+	}).Error
+}
+
+// RevokeSessions moves password_changed_at forward, which invalidates every token issued before now
+func (r *adminRepository) RevokeSessions(ctx context.Context, admin *models.Admin) error {
+	return r.db.WithContext(ctx).Model(admin).Update("password_changed_at", time.Now()).Error
+}
+
+// SetSuspended suspends the account or lifts its suspension
+func (r *adminRepository) SetSuspended(ctx context.Context, admin *models.Admin, suspended bool) error {
+	var suspendedAt *time.Time
+	if suspended {
+		now := time.Now()
+		suspendedAt = &now
+	}
+	if err := r.db.WithContext(ctx).Model(admin).Update("suspended_at", suspendedAt).Error; err != nil {
+		return err
+	}
+	admin.SuspendedAt = suspendedAt
+	return nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"ride-sharing/internal/domains/admin/dto"
+	"ride-sharing/internal/domains/admin/repository"
+	auditModels "ride-sharing/internal/domains/audit/models"
+	auditRepository "ride-sharing/internal/domains/audit/repository"
+	"ride-sharing/internal/pkg/auth"
+	customError "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/password"
+)
+
+type AdminService struct {
+	repo          repository.AdminRepository
+	tokenService  *auth.TokenService
+	userProviders map[auth.UserType]auth.UserProvider
+	auditRepo     auditRepository.AuditRepository
+}
+
+func NewAdminService(repo repository.AdminRepository, tokenService *auth.TokenService, userProviders map[auth.UserType]auth.UserProvider, auditRepo auditRepository.AuditRepository) *AdminService {
+	return &AdminService{
+		repo:          repo,
+		tokenService:  tokenService,
+		userProviders: userProviders,
+		auditRepo:     auditRepo,
+	}
+}
+
+func (s *AdminService) Login(ctx context.Context, req dto.AdminLoginRequest) (*dto.AdminLoginResponse, *customError.AppError) {
+	admin, err := s.repo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if admin == nil || !admin.Active {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeAdmin), "password", "failure").Inc()
+		return nil, customError.NewUnauthorizedError("invalid credentials")
+	}
+
+	match, err := password.CheckPassword(req.Password, admin.Password)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if !match {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeAdmin), "password", "failure").Inc()
+		return nil, customError.NewUnauthorizedError("invalid credentials")
+	}
+	if admin.SuspendedAt != nil {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeAdmin), "password", "failure").Inc()
+		return nil, customError.NewForbiddenError("account suspended")
+	}
+	metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeAdmin), "password", "success").Inc()
+
+	accessToken, err := s.tokenService.GenerateAccessToken(admin.ID.String(), auth.UserTypeAdmin, admin.PasswordChangedAt)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	refreshToken, err := s.tokenService.GenerateRefreshToken(admin.ID.String(), auth.UserTypeAdmin, admin.PasswordChangedAt)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	return &dto.AdminLoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Admin: dto.AdminResponse{
+			ID:       admin.ID,
+			Email:    admin.Email,
+			FullName: admin.FullName,
+		},
+	}, nil
+}
+
+// Impersonate mints a short-lived access token that lets an admin act as the target account.
+// The token carries the admin in its "act" claim and the start of the session is audited.
+func (s *AdminService) Impersonate(ctx context.Context, adminID string, req dto.ImpersonateRequest, clientIP string) (*dto.ImpersonationResponse, *customError.AppError) {
+	userType := auth.UserType(req.UserType)
+	provider, exists := s.userProviders[userType]
+	if !exists {
+		return nil, customError.NewValidationError("invalid request body", map[string]string{
+			"user_type": "Impersonation is not supported for this user type",
+		})
+	}
+
+	target, err := provider.GetByID(ctx, req.UserID, userType)
+	if err != nil {
+		if appErr, ok := err.(*customError.AppError); ok {
+			return nil, appErr
+		}
+		return nil, customError.NewInternalError(err)
+	}
+
+	identity, ok := target.(auth.Identity)
+	if !ok || identity.GetPasswordChangedAt() == nil {
+		return nil, customError.NewConflictError("target account cannot be impersonated")
+	}
+	if identity.GetSuspendedAt() != nil {
+		return nil, customError.NewConflictError("target account is suspended")
+	}
+
+	accessToken, expiresAt, err := s.tokenService.GenerateImpersonationToken(req.UserID, userType, identity.GetPasswordChangedAt(), adminID, auth.UserTypeAdmin)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	requestID, _ := ctx.Value(logging.RequestIDKey).(string)
+	if err := s.auditRepo.Create(ctx, &auditModels.AuditLog{
+		Action:      "impersonation_started",
+		ActorID:     adminID,
+		ActorType:   string(auth.UserTypeAdmin),
+		SubjectID:   req.UserID,
+		SubjectType: req.UserType,
+		IP:          clientIP,
+		RequestID:   requestID,
+		Metadata:    map[string]string{"reason": req.Reason},
+	}); err != nil {
+		// Impersonation must never happen without a trace
+		return nil, customError.NewInternalError(err)
+	}
+
+	return &dto.ImpersonationResponse{
+		AccessToken:    accessToken,
+		ExpiresAt:      expiresAt,
+		UserID:         req.UserID,
+		UserType:       req.UserType,
+		ImpersonatorID: adminID,
+	}, nil
+}
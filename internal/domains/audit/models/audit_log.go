@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditLog records security relevant actions, including every request made while impersonating
+type AuditLog struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Action      string    `gorm:"not null;index"`
+	ActorID     string    `gorm:"not null;index"`
+	ActorType   string    `gorm:"not null"`
+	SubjectID   string    `gorm:"index"`
+	SubjectType string
+	Method      string
+	Path        string
+	Status      int
+	IP          string
+	RequestID   string
+	Metadata    map[string]string `gorm:"serializer:json;type:jsonb"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime;index"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/audit/models"
+
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	// SetStatus records the response status of an entry written before the request was served
+	SetStatus(ctx context.Context, id uuid.UUID, status int) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *auditRepository) SetStatus(ctx context.Context, id uuid.UUID, status int) error {
+	return r.db.WithContext(ctx).Model(&models.AuditLog{}).Where("id = ?", id).Update("status", status).Error
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+
+	"ride-sharing/internal/domains/devices/dto"
+	"ride-sharing/internal/domains/devices/service"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeviceHandler struct {
+	service *service.DeviceService
+}
+
+func NewDeviceHandler(service *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{service: service}
+}
+
+// Register Device godoc
+// @Summary      Register a push device
+// @Description  Register or refresh the push notification token of the current app install
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.RegisterDeviceRequest  true  "Device token"
+// @Success      201      {object}  response.SuccessResponse{data=dto.DeviceResponse}  "Device registered"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /devices [post]
+func (h *DeviceHandler) Register(c *gin.Context) {
+	var req dto.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Register(c.Request.Context(), ownerID, ownerType, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "device registered", res, nil)
+}
+
+// List Devices godoc
+// @Summary      List push devices
+// @Description  List the push devices registered by the current user or rider
+// @Tags         devices
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=[]dto.DeviceResponse}  "Devices fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /devices [get]
+func (h *DeviceHandler) List(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.List(c.Request.Context(), ownerID, ownerType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "devices fetched", res, nil)
+}
+
+// Unregister Device godoc
+// @Summary      Unregister a push device
+// @Description  Stop sending push notifications to a device, e.g. on logout
+// @Tags         devices
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Device ID"
+// @Success      200      {object}  response.SuccessResponse  "Device unregistered"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  response.ErrorResponse  "Device not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /devices/{id} [delete]
+func (h *DeviceHandler) Unregister(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unregister(c.Request.Context(), ownerID, ownerType, c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "device unregistered", nil, nil)
+}
+
+func owner(c *gin.Context) (string, auth.UserType, bool) {
+	userID, exists := c.Get("userID")
+	userType, typeExists := c.Get("userType")
+	if !exists || !typeExists {
+		response.Error(c, errors.NewUnauthorizedError("user ID not found in context"))
+		return "", "", false
+	}
+	return userID.(string), userType.(auth.UserType), true
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=4096"`
+	Platform string `json:"platform" binding:"required,oneof=ios android web"`
+}
+
+type DeviceResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Platform   string    `json:"platform"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models" // Import the common model package
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Platform string
+
+const (
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	PlatformWeb     Platform = "web"
+)
+
+// Device is a push notification token registered by a user or rider app install.
+// A token belongs to at most one owner; registering it again moves it to the new owner.
+type Device struct {
+	CommonModels.Common `swaggerignore:"true"`
+	OwnerID             uuid.UUID `gorm:"type:uuid;not null;index:idx_devices_owner"`
+	OwnerType           string    `gorm:"not null;index:idx_devices_owner"`
+	Platform            Platform  `gorm:"not null"`
+	Token               string    `gorm:"uniqueIndex;not null"`
+	LastSeenAt          time.Time `gorm:"not null"`
+}
+
+func (Device) TableName() string {
+	return "devices"
+}
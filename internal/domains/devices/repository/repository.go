@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/devices/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository interface {
+	Upsert(ctx context.Context, device *models.Device) error
+	ListByOwner(ctx context.Context, ownerID, ownerType string) ([]models.Device, error)
+	Delete(ctx context.Context, ownerID, ownerType, deviceID string) (bool, error)
+	DeleteByTokens(ctx context.Context, tokens []string) error
+}
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+// Upsert registers the token, moving it to the new owner if another account had it
+func (r *deviceRepository) Upsert(ctx context.Context, device *models.Device) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"owner_id":     device.OwnerID,
+			"owner_type":   device.OwnerType,
+			"platform":     device.Platform,
+			"last_seen_at": time.Now(),
+			"updated_at":   time.Now(),
+		}),
+	}).Create(device).Error
+	if err != nil {
+		return err
+	}
+	// Reload so an existing row's ID and timestamps are returned instead of the generated ones
+	return r.db.WithContext(ctx).Where("token = ?", device.Token).First(device).Error
+}
+
+func (r *deviceRepository) ListByOwner(ctx context.Context, ownerID, ownerType string) ([]models.Device, error) {
+	var devices []models.Device
+	if err := r.db.WithContext(ctx).
+		Where("owner_id = ? AND owner_type = ?", ownerID, ownerType).
+		Order("last_seen_at DESC").
+		Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// Delete removes a device owned by the caller. Devices are hard deleted so the token can be registered again.
+func (r *deviceRepository) Delete(ctx context.Context, ownerID, ownerType, deviceID string) (bool, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND owner_id = ? AND owner_type = ?", deviceID, ownerID, ownerType).
+		Delete(&models.Device{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *deviceRepository) DeleteByTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Where("token IN ?", tokens).Delete(&models.Device{}).Error
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"ride-sharing/internal/domains/devices/dto"
+	"ride-sharing/internal/domains/devices/models"
+	"ride-sharing/internal/domains/devices/repository"
+	"ride-sharing/internal/pkg/auth"
+	customError "ride-sharing/internal/pkg/errors"
+	email "ride-sharing/internal/pkg/grpcclient"
+	"ride-sharing/internal/pkg/logging"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DeviceService struct {
+	repo               repository.DeviceRepository
+	notificationClient *email.NotificationClient
+}
+
+func NewDeviceService(repo repository.DeviceRepository, notificationClient *email.NotificationClient) *DeviceService {
+	return &DeviceService{
+		repo:               repo,
+		notificationClient: notificationClient,
+	}
+}
+
+func (s *DeviceService) Register(ctx context.Context, ownerID string, ownerType auth.UserType, req dto.RegisterDeviceRequest) (*dto.DeviceResponse, *customError.AppError) {
+	owner, err := uuid.Parse(ownerID)
+	if err != nil {
+		return nil, customError.NewUnauthorizedError("invalid user ID")
+	}
+
+	device := &models.Device{
+		OwnerID:    owner,
+		OwnerType:  string(ownerType),
+		Platform:   models.Platform(req.Platform),
+		Token:      req.Token,
+		LastSeenAt: time.Now(),
+	}
+	if err := s.repo.Upsert(ctx, device); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res := toDeviceResponse(device)
+	return &res, nil
+}
+
+func (s *DeviceService) List(ctx context.Context, ownerID string, ownerType auth.UserType) ([]dto.DeviceResponse, *customError.AppError) {
+	devices, err := s.repo.ListByOwner(ctx, ownerID, string(ownerType))
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res := make([]dto.DeviceResponse, 0, len(devices))
+	for i := range devices {
+		res = append(res, toDeviceResponse(&devices[i]))
+	}
+	return res, nil
+}
+
+func (s *DeviceService) Unregister(ctx context.Context, ownerID string, ownerType auth.UserType, deviceID string) *customError.AppError {
+	if _, err := uuid.Parse(deviceID); err != nil {
+		return customError.NewNotFoundError("device not found")
+	}
+
+	deleted, err := s.repo.Delete(ctx, ownerID, string(ownerType), deviceID)
+	if err != nil {
+		return customError.NewInternalError(err)
+	}
+	if !deleted {
+		return customError.NewNotFoundError("device not found")
+	}
+	return nil
+}
+
+// Push sends the notification to every device of the owner and removes tokens the
+// notification service reports as invalid. Failures are logged, not returned, since
+// push is best effort and must never fail the operation that triggered it.
+func (s *DeviceService) Push(ctx context.Context, ownerID string, ownerType auth.UserType, title, body string, data map[string]string) {
+	logger := logging.GetLogger().WithContext(ctx)
+
+	devices, err := s.repo.ListByOwner(ctx, ownerID, string(ownerType))
+	if err != nil {
+		logger.Error("failed to load devices for push", zap.String("owner_id", ownerID), zap.Error(err))
+		return
+	}
+
+	var invalid []string
+	for _, device := range devices {
+		err := s.notificationClient.SendPush(ctx, device.Token, title, body, data)
+		switch {
+		case err == nil:
+		case errors.Is(err, email.ErrInvalidDeviceToken):
+			invalid = append(invalid, device.Token)
+		case errors.Is(err, email.ErrCircuitOpen):
+			// The remaining devices would fail the same way
+			logger.Warn("push skipped, notification service unavailable", zap.String("owner_id", ownerID))
+			return
+		default:
+			logger.Warn("push failed", zap.String("device_id", device.ID.String()), zap.Error(err))
+		}
+	}
+
+	if err := s.repo.DeleteByTokens(ctx, invalid); err != nil {
+		logger.Error("failed to prune invalid device tokens", zap.Error(err))
+	}
+}
+
+func toDeviceResponse(device *models.Device) dto.DeviceResponse {
+	return dto.DeviceResponse{
+		ID:         device.ID,
+		Platform:   string(device.Platform),
+		CreatedAt:  device.CreatedAt,
+		LastSeenAt: device.LastSeenAt,
+	}
+}
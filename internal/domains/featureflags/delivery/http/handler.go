@@ -0,0 +1,178 @@
+package http
+
+import (
+	"net/http"
+
+	"ride-sharing/internal/domains/featureflags/dto"
+	"ride-sharing/internal/domains/featureflags/service"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeatureFlagHandler struct {
+	service *service.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(service *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{service: service}
+}
+
+// Create Feature Flag godoc
+// @Summary      Create a feature flag
+// @Description  Create a feature flag with its rollout rules
+// @Tags         feature-flags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.CreateFeatureFlagRequest  true  "Feature flag"
+// @Success      201      {object}  response.SuccessResponse{data=dto.FeatureFlagResponse}  "Feature flag created"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      409      {object}  response.ErrorResponse  "Feature flag already exists"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags [post]
+func (h *FeatureFlagHandler) Create(c *gin.Context) {
+	var req dto.CreateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "feature flag created", res, nil)
+}
+
+// List Feature Flags godoc
+// @Summary      List feature flags
+// @Description  List all feature flags with their rollout rules
+// @Tags         feature-flags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=[]dto.FeatureFlagResponse}  "Feature flags fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags [get]
+func (h *FeatureFlagHandler) List(c *gin.Context) {
+	res, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "feature flags fetched", res, nil)
+}
+
+// Get Feature Flag godoc
+// @Summary      Get a feature flag
+// @Description  Get a feature flag by key
+// @Tags         feature-flags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        key  path  string  true  "Feature flag key"
+// @Success      200      {object}  response.SuccessResponse{data=dto.FeatureFlagResponse}  "Feature flag fetched"
+// @Failure      404      {object}  response.ErrorResponse  "Feature flag not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags/{key} [get]
+func (h *FeatureFlagHandler) Get(c *gin.Context) {
+	res, err := h.service.Get(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "feature flag fetched", res, nil)
+}
+
+// Update Feature Flag godoc
+// @Summary      Update a feature flag
+// @Description  Toggle a feature flag or change its rollout rules; omitted fields are kept
+// @Tags         feature-flags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        key      path  string                        true  "Feature flag key"
+// @Param        request  body  dto.UpdateFeatureFlagRequest  true  "Fields to change"
+// @Success      200      {object}  response.SuccessResponse{data=dto.FeatureFlagResponse}  "Feature flag updated"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      404      {object}  response.ErrorResponse  "Feature flag not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags/{key} [patch]
+func (h *FeatureFlagHandler) Update(c *gin.Context) {
+	var req dto.UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.Update(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "feature flag updated", res, nil)
+}
+
+// Delete Feature Flag godoc
+// @Summary      Delete a feature flag
+// @Description  Delete a feature flag; code checking it will see the feature as off
+// @Tags         feature-flags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        key  path  string  true  "Feature flag key"
+// @Success      200      {object}  response.SuccessResponse{data=bool}  "Feature flag deleted"
+// @Failure      404      {object}  response.ErrorResponse  "Feature flag not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags/{key} [delete]
+func (h *FeatureFlagHandler) Delete(c *gin.Context) {
+	res, err := h.service.Delete(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "feature flag deleted", res, nil)
+}
+
+// Evaluate Feature Flag godoc
+// @Summary      Evaluate a feature flag
+// @Description  Check whether a feature flag is on for a given user, user type and city
+// @Tags         feature-flags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        key      path  string                          true  "Feature flag key"
+// @Param        request  body  dto.EvaluateFeatureFlagRequest  true  "Subject to evaluate for"
+// @Success      200      {object}  response.SuccessResponse{data=dto.EvaluateFeatureFlagResponse}  "Feature flag evaluated"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      404      {object}  response.ErrorResponse  "Feature flag not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/feature-flags/{key}/evaluate [post]
+func (h *FeatureFlagHandler) Evaluate(c *gin.Context) {
+	var req dto.EvaluateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.EvaluateFor(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "feature flag evaluated", res, nil)
+}
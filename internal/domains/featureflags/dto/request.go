@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateFeatureFlagRequest struct {
+	Key         string `json:"key" binding:"required,max=100,flagkey"`
+	Description string `json:"description" binding:"max=500"`
+	Enabled     bool   `json:"enabled"`
+	// Share of users, by hash of their ID, that get the feature; 0 limits it to the allowlist
+	Percentage int      `json:"percentage" binding:"min=0,max=100"`
+	UserTypes  []string `json:"user_types" binding:"omitempty,dive,oneof=user rider admin service"`
+	Cities     []string `json:"cities" binding:"omitempty,dive,required,max=100"`
+	Allowlist  []string `json:"allowlist" binding:"omitempty,max=1000,dive,required"`
+}
+
+// UpdateFeatureFlagRequest changes only the fields that are present
+type UpdateFeatureFlagRequest struct {
+	Description *string   `json:"description" binding:"omitempty,max=500"`
+	Enabled     *bool     `json:"enabled"`
+	Percentage  *int      `json:"percentage" binding:"omitempty,min=0,max=100"`
+	UserTypes   *[]string `json:"user_types" binding:"omitempty,dive,oneof=user rider admin service"`
+	Cities      *[]string `json:"cities" binding:"omitempty,dive,required,max=100"`
+	Allowlist   *[]string `json:"allowlist" binding:"omitempty,max=1000,dive,required"`
+}
+
+type EvaluateFeatureFlagRequest struct {
+	UserID   string `json:"user_id"`
+	UserType string `json:"user_type" binding:"omitempty,oneof=user rider admin service"`
+	City     string `json:"city"`
+}
+
+type FeatureFlagResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Key         string    `json:"key"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	Percentage  int       `json:"percentage"`
+	UserTypes   []string  `json:"user_types"`
+	Cities      []string  `json:"cities"`
+	Allowlist   []string  `json:"allowlist"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type EvaluateFeatureFlagResponse struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models"
+)
+
+// FeatureFlag gates a feature at runtime. A disabled flag is off for everyone; an enabled
+// flag is on for allowlisted users and for the Percentage of the remaining users that
+// match the UserTypes and Cities restrictions (empty means no restriction).
+type FeatureFlag struct {
+	CommonModels.Common `swaggerignore:"true"`
+	Key                 string `gorm:"unique;not null"`
+	Description         string
+	Enabled             bool     `gorm:"not null;default:false"`
+	Percentage          int      `gorm:"not null;default:0"`
+	UserTypes           []string `gorm:"serializer:json;type:jsonb;not null"`
+	Cities              []string `gorm:"serializer:json;type:jsonb;not null"`
+	Allowlist           []string `gorm:"serializer:json;type:jsonb;not null"`
+}
+
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
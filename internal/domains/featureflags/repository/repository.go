@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/featureflags/models"
+
+	"gorm.io/gorm"
+)
+
+type FeatureFlagRepository interface {
+	Create(ctx context.Context, flag *models.FeatureFlag) error
+	GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	List(ctx context.Context) ([]models.FeatureFlag, error)
+	Update(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, flag *models.FeatureFlag) error
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) Create(ctx context.Context, flag *models.FeatureFlag) error {
+	return r.db.WithContext(ctx).Create(flag).Error
+}
+
+func (r *featureFlagRepository) GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&flag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	var flags []models.FeatureFlag
+	if err := r.db.WithContext(ctx).Order("key").Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+func (r *featureFlagRepository) Update(ctx context.Context, flag *models.FeatureFlag) error {
+	return r.db.WithContext(ctx).Save(flag).Error
+}
+
+// Delete removes the flag for good so its key can be reused
+func (r *featureFlagRepository) Delete(ctx context.Context, flag *models.FeatureFlag) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(flag).Error
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"ride-sharing/internal/domains/featureflags/models"
+)
+
+// Subject is who a flag is evaluated for. Any field may be empty, e.g. before login.
+type Subject struct {
+	UserID   string
+	UserType string
+	City     string
+}
+
+// Evaluate resolves flag for subject:
+//   - missing or disabled flags are off
+//   - allowlisted user IDs are on
+//   - subjects outside the user type or city restrictions are off
+//   - everyone else is bucketed by a hash of flag key and user ID, so each user keeps the
+//     same answer as the percentage grows; subjects without an ID only pass at 100%
+func Evaluate(flag *models.FeatureFlag, subject Subject) bool {
+	if flag == nil || !flag.Enabled {
+		return false
+	}
+	if subject.UserID != "" && slices.Contains(flag.Allowlist, subject.UserID) {
+		return true
+	}
+	if len(flag.UserTypes) > 0 && !slices.Contains(flag.UserTypes, subject.UserType) {
+		return false
+	}
+	if len(flag.Cities) > 0 && !slices.ContainsFunc(flag.Cities, func(city string) bool {
+		return strings.EqualFold(city, subject.City)
+	}) {
+		return false
+	}
+
+	if flag.Percentage >= 100 {
+		return true
+	}
+	if flag.Percentage <= 0 || subject.UserID == "" {
+		return false
+	}
+	return bucket(flag.Key, subject.UserID) < flag.Percentage
+}
+
+// bucket maps a user to 0-99. The flag key is part of the hash so each flag rolls out
+// to a different slice of users.
+func bucket(key, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"ride-sharing/internal/domains/featureflags/dto"
+	"ride-sharing/internal/domains/featureflags/models"
+	"ride-sharing/internal/domains/featureflags/repository"
+	customError "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// FlagPasswordlessLogin gates login with one-time codes, see UserService.RequestLoginCode
+const FlagPasswordlessLogin = "passwordless_login"
+
+// cachedFlag is what is stored in Redis. Missing flags are cached too, with Flag unset,
+// so unknown keys do not hit Postgres on every evaluation.
+type cachedFlag struct {
+	Flag *models.FeatureFlag `json:"flag"`
+}
+
+type FeatureFlagService struct {
+	repo     repository.FeatureFlagRepository
+	cache    *redis.Cache
+	cacheTTL time.Duration
+}
+
+// NewFeatureFlagService serves flags from Redis for cacheTTL. Changes made through this
+// service invalidate the cache right away; other replicas see them within cacheTTL.
+func NewFeatureFlagService(repo repository.FeatureFlagRepository, cache *redis.Cache, cacheTTL time.Duration) *FeatureFlagService {
+	return &FeatureFlagService{
+		repo:     repo,
+		cache:    cache,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Enabled evaluates the flag for subject. It fails closed: unknown flags and lookup
+// errors turn the feature off.
+func (s *FeatureFlagService) Enabled(ctx context.Context, key string, subject Subject) bool {
+	flag, err := s.load(ctx, key)
+	if err != nil {
+		logging.GetLogger().WithContext(ctx).Error("failed to load feature flag", zap.String("flag", key), zap.Error(err))
+		metrics.FeatureFlagEvaluationsTotal.WithLabelValues(key, "error").Inc()
+		return false
+	}
+
+	enabled := Evaluate(flag, subject)
+	result := "off"
+	if enabled {
+		result = "on"
+	}
+	metrics.FeatureFlagEvaluationsTotal.WithLabelValues(key, result).Inc()
+	return enabled
+}
+
+func (s *FeatureFlagService) Create(ctx context.Context, req dto.CreateFeatureFlagRequest) (*dto.FeatureFlagResponse, *customError.AppError) {
+	existing, err := s.repo.GetByKey(ctx, req.Key)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if existing != nil {
+		return nil, customError.NewConflictError("feature flag already exists")
+	}
+
+	flag := &models.FeatureFlag{
+		Key:         req.Key,
+		Description: req.Description,
+		Enabled:     req.Enabled,
+		Percentage:  req.Percentage,
+		UserTypes:   nonNil(req.UserTypes),
+		Cities:      nonNil(req.Cities),
+		Allowlist:   nonNil(req.Allowlist),
+	}
+	if err := s.repo.Create(ctx, flag); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	// A miss may have been cached before the flag existed
+	s.invalidate(ctx, flag.Key)
+
+	res := toFeatureFlagResponse(flag)
+	return &res, nil
+}
+
+func (s *FeatureFlagService) List(ctx context.Context) ([]dto.FeatureFlagResponse, *customError.AppError) {
+	flags, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res := make([]dto.FeatureFlagResponse, 0, len(flags))
+	for i := range flags {
+		res = append(res, toFeatureFlagResponse(&flags[i]))
+	}
+	return res, nil
+}
+
+func (s *FeatureFlagService) Get(ctx context.Context, key string) (*dto.FeatureFlagResponse, *customError.AppError) {
+	flag, appErr := s.get(ctx, key)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	res := toFeatureFlagResponse(flag)
+	return &res, nil
+}
+
+func (s *FeatureFlagService) Update(ctx context.Context, key string, req dto.UpdateFeatureFlagRequest) (*dto.FeatureFlagResponse, *customError.AppError) {
+	flag, appErr := s.get(ctx, key)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if req.Description != nil {
+		flag.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		flag.Enabled = *req.Enabled
+	}
+	if req.Percentage != nil {
+		flag.Percentage = *req.Percentage
+	}
+	if req.UserTypes != nil {
+		flag.UserTypes = nonNil(*req.UserTypes)
+	}
+	if req.Cities != nil {
+		flag.Cities = nonNil(*req.Cities)
+	}
+	if req.Allowlist != nil {
+		flag.Allowlist = nonNil(*req.Allowlist)
+	}
+
+	if err := s.repo.Update(ctx, flag); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	s.invalidate(ctx, flag.Key)
+
+	res := toFeatureFlagResponse(flag)
+	return &res, nil
+}
+
+func (s *FeatureFlagService) Delete(ctx context.Context, key string) (bool, *customError.AppError) {
+	flag, appErr := s.get(ctx, key)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	if err := s.repo.Delete(ctx, flag); err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	s.invalidate(ctx, flag.Key)
+	return true, nil
+}
+
+// EvaluateFor reports how the flag resolves for subject, for checking a rollout from the admin API
+func (s *FeatureFlagService) EvaluateFor(ctx context.Context, key string, req dto.EvaluateFeatureFlagRequest) (*dto.EvaluateFeatureFlagResponse, *customError.AppError) {
+	flag, appErr := s.get(ctx, key)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	subject := Subject{UserID: req.UserID, UserType: req.UserType, City: req.City}
+	return &dto.EvaluateFeatureFlagResponse{Key: flag.Key, Enabled: Evaluate(flag, subject)}, nil
+}
+
+func (s *FeatureFlagService) get(ctx context.Context, key string) (*models.FeatureFlag, *customError.AppError) {
+	flag, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if flag == nil {
+		return nil, customError.NewNotFoundError("feature flag not found")
+	}
+	return flag, nil
+}
+
+// load reads the flag through the cache. A Redis outage falls back to Postgres.
+func (s *FeatureFlagService) load(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var cached cachedFlag
+	hit, err := s.cache.Get(ctx, key, &cached)
+	if err == nil && hit {
+		return cached.Flag, nil
+	}
+
+	flag, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, key, cachedFlag{Flag: flag}, s.cacheTTL); err != nil {
+		logging.GetLogger().WithContext(ctx).Warn("failed to cache feature flag", zap.String("flag", key), zap.Error(err))
+	}
+	return flag, nil
+}
+
+func (s *FeatureFlagService) invalidate(ctx context.Context, key string) {
+	if err := s.cache.Delete(ctx, key); err != nil {
+		logging.GetLogger().WithContext(ctx).Warn("failed to invalidate feature flag", zap.String("flag", key), zap.Error(err))
+	}
+}
+
+func toFeatureFlagResponse(flag *models.FeatureFlag) dto.FeatureFlagResponse {
+	return dto.FeatureFlagResponse{
+		ID:          flag.ID,
+		Key:         flag.Key,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Percentage:  flag.Percentage,
+		UserTypes:   nonNil(flag.UserTypes),
+		Cities:      nonNil(flag.Cities),
+		Allowlist:   nonNil(flag.Allowlist),
+		CreatedAt:   flag.CreatedAt,
+		UpdatedAt:   flag.UpdatedAt,
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
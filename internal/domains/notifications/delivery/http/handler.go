@@ -0,0 +1,251 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"ride-sharing/internal/domains/notifications/dto"
+	"ride-sharing/internal/domains/notifications/service"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keepAliveInterval keeps idle streams open through proxies that drop silent connections
+const keepAliveInterval = 25 * time.Second
+
+type NotificationHandler struct {
+	preferences *service.PreferenceService
+	inbox       *service.InboxService
+}
+
+func NewNotificationHandler(preferences *service.PreferenceService, inbox *service.InboxService) *NotificationHandler {
+	return &NotificationHandler{preferences: preferences, inbox: inbox}
+}
+
+// Get Preferences godoc
+// @Summary      Get notification preferences
+// @Description  Channels enabled per category and quiet hours of the current user or rider
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=dto.PreferencesResponse}  "Preferences fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.preferences.Get(c.Request.Context(), ownerID, ownerType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "notification preferences fetched", res, nil)
+}
+
+// Update Preferences godoc
+// @Summary      Update notification preferences
+// @Description  Opt in or out of categories per channel and set quiet hours. Security notifications cannot be turned off.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.UpdatePreferencesRequest  true  "Preference changes"
+// @Success      200      {object}  response.SuccessResponse{data=dto.PreferencesResponse}  "Preferences updated"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.preferences.Update(c.Request.Context(), ownerID, ownerType, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "notification preferences updated", res, nil)
+}
+
+// List Inbox godoc
+// @Summary      List inbox notifications
+// @Description  Paginated in-app notifications, newest first, with the unread count in meta
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page      query  int  false  "Page number"  default(1)
+// @Param        per_page  query  int  false  "Items per page"  default(20)
+// @Success      200      {object}  response.SuccessResponse{data=[]dto.InboxItemResponse,meta=dto.InboxMeta}  "Inbox fetched"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/inbox [get]
+func (h *NotificationHandler) ListInbox(c *gin.Context) {
+	var query dto.InboxQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid query parameters", details))
+		return
+	}
+
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, meta, err := h.inbox.List(c.Request.Context(), ownerID, ownerType, query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "inbox fetched", res, meta)
+}
+
+// Unread Count godoc
+// @Summary      Count unread notifications
+// @Description  Number of unread in-app notifications, for badges
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=dto.UnreadCountResponse}  "Unread count fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/inbox/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.inbox.UnreadCount(c.Request.Context(), ownerID, ownerType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "unread count fetched", res, nil)
+}
+
+// Mark Read godoc
+// @Summary      Mark a notification as read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Notification ID"
+// @Success      200      {object}  response.SuccessResponse  "Notification marked as read"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  response.ErrorResponse  "Notification not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/inbox/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	if err := h.inbox.MarkRead(c.Request.Context(), ownerID, ownerType, c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "notification marked as read", nil, nil)
+}
+
+// Mark All Read godoc
+// @Summary      Mark all notifications as read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=dto.MarkAllReadResponse}  "Notifications marked as read"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/inbox/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.inbox.MarkAllRead(c.Request.Context(), ownerID, ownerType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "notifications marked as read", res, nil)
+}
+
+// Stream Inbox godoc
+// @Summary      Stream new notifications
+// @Description  Server-Sent Events stream emitting a "notification" event with each new inbox item while connected
+// @Tags         notifications
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Success      200      {object}  dto.InboxItemResponse  "Event stream"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /notifications/inbox/stream [get]
+func (h *NotificationHandler) StreamInbox(c *gin.Context) {
+	ownerID, ownerType, ok := owner(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.inbox.Subscribe(c.Request.Context(), ownerID, ownerType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", string(item))
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", "")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func owner(c *gin.Context) (string, auth.UserType, bool) {
+	userID, exists := c.Get("userID")
+	userType, typeExists := c.Get("userType")
+	if !exists || !typeExists {
+		response.Error(c, errors.NewUnauthorizedError("user ID not found in context"))
+		return "", "", false
+	}
+	return userID.(string), userType.(auth.UserType), true
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"ride-sharing/internal/domains/notifications/dto"
+	customErrors "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/templates"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templates *templates.Renderer
+}
+
+func NewTemplateHandler(renderer *templates.Renderer) *TemplateHandler {
+	return &TemplateHandler{templates: renderer}
+}
+
+// List Templates godoc
+// @Summary      List notification templates
+// @Description  Template names with their versions and the locales of the latest version
+// @Tags         notification-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=[]templates.Info}  "Templates fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Router       /admin/notification-templates [get]
+func (h *TemplateHandler) List(c *gin.Context) {
+	response.Success(c, http.StatusOK, "templates fetched", h.templates.List(), nil)
+}
+
+// Preview Template godoc
+// @Summary      Preview a notification template
+// @Description  Render a template with sample data in the requested locale, falling back like real sends do
+// @Tags         notification-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.PreviewTemplateRequest  true  "Template, locale and sample data"
+// @Success      200      {object}  response.SuccessResponse{data=templates.Rendered}  "Template rendered"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error or template failed to render"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404      {object}  response.ErrorResponse  "Template not found"
+// @Router       /admin/notification-templates/preview [post]
+func (h *TemplateHandler) Preview(c *gin.Context) {
+	var req dto.PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, customErrors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	rendered, err := h.templates.Render(req.Template, req.Locale, req.Version, req.Data)
+	if err != nil {
+		if errors.Is(err, templates.ErrTemplateNotFound) {
+			response.Error(c, customErrors.NewNotFoundError(err.Error()))
+			return
+		}
+		response.Error(c, customErrors.NewValidationError("template failed to render", err.Error()))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "template rendered", rendered, nil)
+}
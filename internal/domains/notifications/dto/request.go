@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuietHours struct {
+	Start    string `json:"start" binding:"required,datetime=15:04" example:"22:00"`
+	End      string `json:"end" binding:"required,datetime=15:04" example:"07:00"`
+	Timezone string `json:"timezone" binding:"required,timezone" example:"Asia/Kathmandu"`
+}
+
+// UpdatePreferencesRequest changes only the categories and channels present in the request.
+// Send quiet_hours to set the window, or clear_quiet_hours to turn it off.
+type UpdatePreferencesRequest struct {
+	Channels        map[string]map[string]bool `json:"channels"`
+	QuietHours      *QuietHours                `json:"quiet_hours"`
+	ClearQuietHours bool                       `json:"clear_quiet_hours"`
+}
+
+type PreferencesResponse struct {
+	Channels            map[string]map[string]bool `json:"channels"`
+	QuietHours          *QuietHours                `json:"quiet_hours"`
+	MandatoryCategories []string                   `json:"mandatory_categories"`
+}
+
+type InboxQuery struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type InboxItemResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	Category  string            `json:"category"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	ReadAt    *time.Time        `json:"read_at"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type InboxMeta struct {
+	Page        int   `json:"page"`
+	PerPage     int   `json:"per_page"`
+	Total       int64 `json:"total"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+type PreviewTemplateRequest struct {
+	Template string            `json:"template" binding:"required" example:"register_otp"`
+	Locale   string            `json:"locale" binding:"omitempty,bcp47_language_tag" example:"ne"`
+	Version  int               `json:"version" binding:"omitempty,min=1"`
+	Data     map[string]string `json:"data" example:"OTP:123456"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models" // Import the common model package
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboxItem is an in-app notification kept until it ages out of the retention window
+type InboxItem struct {
+	CommonModels.Common `swaggerignore:"true"`
+	OwnerID             uuid.UUID         `gorm:"type:uuid;not null;index:idx_inbox_items_owner"`
+	OwnerType           string            `gorm:"not null;index:idx_inbox_items_owner"`
+	Category            Category          `gorm:"not null"`
+	Title               string            `gorm:"not null"`
+	Body                string            `gorm:"not null"`
+	Data                map[string]string `gorm:"serializer:json;type:jsonb"`
+	ReadAt              *time.Time        `gorm:"index"`
+}
+
+func (InboxItem) TableName() string {
+	return "inbox_items"
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models" // Import the common model package
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Category string
+
+const (
+	CategorySecurity    Category = "security"
+	CategoryTripUpdates Category = "trip_updates"
+	CategoryPromotions  Category = "promotions"
+	CategoryReceipts    Category = "receipts"
+)
+
+var Categories = []Category{CategorySecurity, CategoryTripUpdates, CategoryPromotions, CategoryReceipts}
+
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelSMS   Channel = "sms"
+	ChannelPush  Channel = "push"
+	ChannelInApp Channel = "in_app"
+)
+
+var Channels = []Channel{ChannelEmail, ChannelSMS, ChannelPush, ChannelInApp}
+
+// Mandatory reports whether messages in the category are always delivered regardless of preferences
+func (c Category) Mandatory() bool {
+	return c == CategorySecurity
+}
+
+// BypassesQuietHours reports whether the category is time critical enough to interrupt quiet hours
+func (c Category) BypassesQuietHours() bool {
+	return c == CategorySecurity || c == CategoryTripUpdates
+}
+
+// Interruptive reports whether the channel alerts the recipient immediately
+func (c Channel) Interruptive() bool {
+	return c == ChannelPush || c == ChannelSMS
+}
+
+// NotificationPreference stores which channels an owner wants per category, plus an optional
+// quiet hours window in the owner's timezone. Missing entries fall back to the defaults.
+type NotificationPreference struct {
+	CommonModels.Common `swaggerignore:"true"`
+	OwnerID             uuid.UUID                     `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_owner"`
+	OwnerType           string                        `gorm:"not null;uniqueIndex:idx_notification_preferences_owner"`
+	Channels            map[Category]map[Channel]bool `gorm:"serializer:json;type:jsonb;not null"`
+	QuietHoursStart     *string                       // "HH:MM", nil when quiet hours are off
+	QuietHoursEnd       *string
+	Timezone            string `gorm:"not null;default:UTC"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultChannels enables everything except promotional SMS
+func DefaultChannels() map[Category]map[Channel]bool {
+	channels := make(map[Category]map[Channel]bool, len(Categories))
+	for _, category := range Categories {
+		channels[category] = make(map[Channel]bool, len(Channels))
+		for _, channel := range Channels {
+			channels[category][channel] = true
+		}
+	}
+	channels[CategoryPromotions][ChannelSMS] = false
+	return channels
+}
+
+// Allows reports whether the owner accepts the category on the channel
+func (p *NotificationPreference) Allows(category Category, channel Channel) bool {
+	if category.Mandatory() {
+		return true
+	}
+	if enabled, ok := p.Channels[category][channel]; ok {
+		return enabled
+	}
+	return DefaultChannels()[category][channel]
+}
+
+// InQuietHours reports whether now falls in the owner's quiet hours window, which may wrap past midnight
+func (p *NotificationPreference) InQuietHours(now time.Time) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil {
+		return false
+	}
+	start, errStart := time.Parse("15:04", *p.QuietHoursStart)
+	end, errEnd := time.Parse("15:04", *p.QuietHoursEnd)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	if startMinute <= endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/notifications/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InboxRepository interface {
+	Create(ctx context.Context, item *models.InboxItem) error
+	List(ctx context.Context, ownerID, ownerType string, offset, limit int) ([]models.InboxItem, int64, error)
+	UnreadCount(ctx context.Context, ownerID, ownerType string) (int64, error)
+	MarkRead(ctx context.Context, ownerID, ownerType, itemID string) (bool, error)
+	MarkAllRead(ctx context.Context, ownerID, ownerType string) (int64, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type inboxRepository struct {
+	db *gorm.DB
+}
+
+func NewInboxRepository(db *gorm.DB) InboxRepository {
+	return &inboxRepository{db: db}
+}
+
+func (r *inboxRepository) Create(ctx context.Context, item *models.InboxItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// List returns a page of the owner's items, newest first, and the total item count
+func (r *inboxRepository) List(ctx context.Context, ownerID, ownerType string, offset, limit int) ([]models.InboxItem, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.InboxItem{}).Where("owner_id = ? AND owner_type = ?", ownerID, ownerType)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []models.InboxItem
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *inboxRepository) UnreadCount(ctx context.Context, ownerID, ownerType string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.InboxItem{}).
+		Where("owner_id = ? AND owner_type = ? AND read_at IS NULL", ownerID, ownerType).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead reports whether the item exists for the owner; marking an already read item is a no-op
+func (r *inboxRepository) MarkRead(ctx context.Context, ownerID, ownerType, itemID string) (bool, error) {
+	var item models.InboxItem
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND owner_id = ? AND owner_type = ?", itemID, ownerID, ownerType).
+		First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if item.ReadAt != nil {
+		return true, nil
+	}
+	return true, r.db.WithContext(ctx).Model(&item).Update("read_at", time.Now()).Error
+}
+
+func (r *inboxRepository) MarkAllRead(ctx context.Context, ownerID, ownerType string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.InboxItem{}).
+		Where("owner_id = ? AND owner_type = ? AND read_at IS NULL", ownerID, ownerType).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// DeleteOlderThan permanently removes items created before cutoff
+func (r *inboxRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("created_at < ?", cutoff).Delete(&models.InboxItem{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/notifications/models"
+
+	"gorm.io/gorm"
+)
+
+type PreferenceRepository interface {
+	Get(ctx context.Context, ownerID, ownerType string) (*models.NotificationPreference, error)
+	Save(ctx context.Context, preference *models.NotificationPreference) error
+}
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+func (r *preferenceRepository) Get(ctx context.Context, ownerID, ownerType string) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("owner_id = ? AND owner_type = ?", ownerID, ownerType).First(&preference).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// Save inserts the owner's first preferences or updates the existing row
+func (r *preferenceRepository) Save(ctx context.Context, preference *models.NotificationPreference) error {
+	return r.db.WithContext(ctx).Save(preference).Error
+}
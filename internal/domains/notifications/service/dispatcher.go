@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"ride-sharing/internal/domains/notifications/models"
+	"ride-sharing/internal/domains/notifications/repository"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/logging"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notification is a message for one recipient, offered on one or more channels
+type Notification struct {
+	OwnerID   string
+	OwnerType auth.UserType
+	Category  models.Category
+	Channels  []models.Channel
+	Title     string
+	Body      string
+	Data      map[string]string
+}
+
+// Sender delivers a notification on a single channel
+type Sender interface {
+	Send(ctx context.Context, notification Notification) error
+}
+
+// Dispatcher is the single entry point for user facing notifications. It applies the
+// recipient's opt-outs and quiet hours before handing the message to each channel's sender;
+// security notifications skip both checks.
+type Dispatcher struct {
+	preferences repository.PreferenceRepository
+	senders     map[models.Channel]Sender
+}
+
+func NewDispatcher(preferences repository.PreferenceRepository) *Dispatcher {
+	return &Dispatcher{
+		preferences: preferences,
+		senders:     make(map[models.Channel]Sender),
+	}
+}
+
+// RegisterSender sets the sender used for a channel. It must be called before Dispatch.
+func (d *Dispatcher) RegisterSender(channel models.Channel, sender Sender) {
+	d.senders[channel] = sender
+}
+
+// Dispatch delivers the notification on every requested channel the recipient accepts.
+// Delivery is best effort: failures are logged so the triggering operation never fails.
+func (d *Dispatcher) Dispatch(ctx context.Context, notification Notification) {
+	logger := logging.GetLogger().WithContext(ctx)
+
+	preference, err := loadPreference(ctx, d.preferences, notification.OwnerID, notification.OwnerType)
+	if err != nil {
+		if !notification.Category.Mandatory() {
+			logger.Error("failed to load notification preferences", zap.String("owner_id", notification.OwnerID), zap.Error(err))
+			return
+		}
+		// Security messages still go out on the defaults
+		preference = &models.NotificationPreference{Channels: models.DefaultChannels()}
+	}
+
+	quiet := !notification.Category.BypassesQuietHours() && preference.InQuietHours(time.Now())
+	for _, channel := range notification.Channels {
+		if !preference.Allows(notification.Category, channel) {
+			continue
+		}
+		if quiet && channel.Interruptive() {
+			continue
+		}
+
+		sender, ok := d.senders[channel]
+		if !ok {
+			continue
+		}
+		if err := sender.Send(ctx, notification); err != nil {
+			logger.Warn("notification delivery failed",
+				zap.String("owner_id", notification.OwnerID),
+				zap.String("category", string(notification.Category)),
+				zap.String("channel", string(channel)),
+				zap.Error(err),
+			)
+		}
+	}
+}
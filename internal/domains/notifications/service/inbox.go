@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ride-sharing/internal/domains/notifications/dto"
+	"ride-sharing/internal/domains/notifications/models"
+	"ride-sharing/internal/domains/notifications/repository"
+	"ride-sharing/internal/pkg/auth"
+	customError "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/redis"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const defaultInboxPageSize = 20
+
+type InboxService struct {
+	repo   repository.InboxRepository
+	pubsub *redis.PubSub
+}
+
+func NewInboxService(repo repository.InboxRepository, pubsub *redis.PubSub) *InboxService {
+	return &InboxService{repo: repo, pubsub: pubsub}
+}
+
+func (s *InboxService) List(ctx context.Context, ownerID string, ownerType auth.UserType, query dto.InboxQuery) ([]dto.InboxItemResponse, *dto.InboxMeta, *customError.AppError) {
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PerPage == 0 {
+		query.PerPage = defaultInboxPageSize
+	}
+
+	items, total, err := s.repo.List(ctx, ownerID, string(ownerType), (query.Page-1)*query.PerPage, query.PerPage)
+	if err != nil {
+		return nil, nil, customError.NewInternalError(err)
+	}
+	unread, err := s.repo.UnreadCount(ctx, ownerID, string(ownerType))
+	if err != nil {
+		return nil, nil, customError.NewInternalError(err)
+	}
+
+	res := make([]dto.InboxItemResponse, 0, len(items))
+	for i := range items {
+		res = append(res, toInboxItemResponse(&items[i]))
+	}
+	return res, &dto.InboxMeta{Page: query.Page, PerPage: query.PerPage, Total: total, UnreadCount: unread}, nil
+}
+
+func (s *InboxService) UnreadCount(ctx context.Context, ownerID string, ownerType auth.UserType) (*dto.UnreadCountResponse, *customError.AppError) {
+	unread, err := s.repo.UnreadCount(ctx, ownerID, string(ownerType))
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	return &dto.UnreadCountResponse{UnreadCount: unread}, nil
+}
+
+func (s *InboxService) MarkRead(ctx context.Context, ownerID string, ownerType auth.UserType, itemID string) *customError.AppError {
+	if _, err := uuid.Parse(itemID); err != nil {
+		return customError.NewNotFoundError("notification not found")
+	}
+
+	found, err := s.repo.MarkRead(ctx, ownerID, string(ownerType), itemID)
+	if err != nil {
+		return customError.NewInternalError(err)
+	}
+	if !found {
+		return customError.NewNotFoundError("notification not found")
+	}
+	return nil
+}
+
+func (s *InboxService) MarkAllRead(ctx context.Context, ownerID string, ownerType auth.UserType) (*dto.MarkAllReadResponse, *customError.AppError) {
+	updated, err := s.repo.MarkAllRead(ctx, ownerID, string(ownerType))
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	return &dto.MarkAllReadResponse{Updated: updated}, nil
+}
+
+// Add stores a notification in the recipient's inbox and pushes it to any open stream
+func (s *InboxService) Add(ctx context.Context, notification Notification) error {
+	owner, err := uuid.Parse(notification.OwnerID)
+	if err != nil {
+		return fmt.Errorf("invalid inbox owner %q: %w", notification.OwnerID, err)
+	}
+
+	item := &models.InboxItem{
+		OwnerID:   owner,
+		OwnerType: string(notification.OwnerType),
+		Category:  notification.Category,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+	}
+	if err := s.repo.Create(ctx, item); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(toInboxItemResponse(item))
+	if err != nil {
+		return err
+	}
+	// The item is already stored, so a missed real-time push is picked up on the next fetch
+	if err := s.pubsub.Publish(ctx, inboxChannel(notification.OwnerID, notification.OwnerType), payload); err != nil {
+		logging.GetLogger().WithContext(ctx).Warn("failed to publish inbox item", zap.Error(err))
+	}
+	return nil
+}
+
+// Subscribe streams new inbox items of the owner, as JSON, until ctx is done
+func (s *InboxService) Subscribe(ctx context.Context, ownerID string, ownerType auth.UserType) (<-chan []byte, *customError.AppError) {
+	items, err := s.pubsub.Subscribe(ctx, inboxChannel(ownerID, ownerType))
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	return items, nil
+}
+
+// RunRetention deletes inbox items older than retention once an hour until ctx is cancelled
+func (s *InboxService) RunRetention(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.repo.DeleteOlderThan(ctx, time.Now().Add(-retention))
+		if err != nil && ctx.Err() == nil {
+			logging.GetLogger().Error("inbox retention cleanup failed", zap.Error(err))
+		} else if deleted > 0 {
+			logging.GetLogger().Info("inbox retention cleanup", zap.Int64("deleted", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func inboxChannel(ownerID string, ownerType auth.UserType) string {
+	return fmt.Sprintf("inbox:%s:%s", ownerType, ownerID)
+}
+
+func toInboxItemResponse(item *models.InboxItem) dto.InboxItemResponse {
+	return dto.InboxItemResponse{
+		ID:        item.ID,
+		Category:  string(item.Category),
+		Title:     item.Title,
+		Body:      item.Body,
+		Data:      item.Data,
+		ReadAt:    item.ReadAt,
+		CreatedAt: item.CreatedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ride-sharing/internal/domains/notifications/dto"
+	"ride-sharing/internal/domains/notifications/models"
+	"ride-sharing/internal/domains/notifications/repository"
+	"ride-sharing/internal/pkg/auth"
+	customError "ride-sharing/internal/pkg/errors"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type PreferenceService struct {
+	repo repository.PreferenceRepository
+}
+
+func NewPreferenceService(repo repository.PreferenceRepository) *PreferenceService {
+	return &PreferenceService{repo: repo}
+}
+
+func (s *PreferenceService) Get(ctx context.Context, ownerID string, ownerType auth.UserType) (*dto.PreferencesResponse, *customError.AppError) {
+	preference, err := loadPreference(ctx, s.repo, ownerID, ownerType)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	return toPreferencesResponse(preference), nil
+}
+
+func (s *PreferenceService) Update(ctx context.Context, ownerID string, ownerType auth.UserType, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, *customError.AppError) {
+	details := map[string]string{}
+	for category, channels := range req.Channels {
+		if !slices.Contains(models.Categories, models.Category(category)) {
+			details["channels."+category] = "Unknown notification category"
+			continue
+		}
+		if models.Category(category).Mandatory() {
+			details["channels."+category] = "Security notifications cannot be turned off"
+			continue
+		}
+		for channel := range channels {
+			if !slices.Contains(models.Channels, models.Channel(channel)) {
+				details[fmt.Sprintf("channels.%s.%s", category, channel)] = "Unknown notification channel"
+			}
+		}
+	}
+	if len(details) > 0 {
+		return nil, customError.NewValidationError("invalid request body", details)
+	}
+
+	owner, err := uuid.Parse(ownerID)
+	if err != nil {
+		return nil, customError.NewUnauthorizedError("invalid user ID")
+	}
+	preference, err := loadPreference(ctx, s.repo, ownerID, ownerType)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	preference.OwnerID = owner
+
+	for category, channels := range req.Channels {
+		for channel, enabled := range channels {
+			preference.Channels[models.Category(category)][models.Channel(channel)] = enabled
+		}
+	}
+	if req.ClearQuietHours {
+		preference.QuietHoursStart, preference.QuietHoursEnd = nil, nil
+	}
+	if req.QuietHours != nil {
+		preference.QuietHoursStart = &req.QuietHours.Start
+		preference.QuietHoursEnd = &req.QuietHours.End
+		preference.Timezone = req.QuietHours.Timezone
+	}
+
+	if err := s.repo.Save(ctx, preference); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	return toPreferencesResponse(preference), nil
+}
+
+// loadPreference returns the stored preferences merged over the defaults, or just the
+// defaults for owners who never changed anything.
+func loadPreference(ctx context.Context, repo repository.PreferenceRepository, ownerID string, ownerType auth.UserType) (*models.NotificationPreference, error) {
+	stored, err := repo.Get(ctx, ownerID, string(ownerType))
+	if err != nil {
+		return nil, err
+	}
+
+	channels := models.DefaultChannels()
+	if stored == nil {
+		return &models.NotificationPreference{OwnerType: string(ownerType), Channels: channels, Timezone: "UTC"}, nil
+	}
+	for category, values := range stored.Channels {
+		if _, known := channels[category]; !known {
+			continue
+		}
+		for channel, enabled := range values {
+			channels[category][channel] = enabled
+		}
+	}
+	stored.Channels = channels
+	return stored, nil
+}
+
+func toPreferencesResponse(preference *models.NotificationPreference) *dto.PreferencesResponse {
+	channels := make(map[string]map[string]bool, len(models.Categories))
+	var mandatory []string
+	for _, category := range models.Categories {
+		channels[string(category)] = make(map[string]bool, len(models.Channels))
+		for _, channel := range models.Channels {
+			channels[string(category)][string(channel)] = preference.Allows(category, channel)
+		}
+		if category.Mandatory() {
+			mandatory = append(mandatory, string(category))
+		}
+	}
+
+	res := &dto.PreferencesResponse{Channels: channels, MandatoryCategories: mandatory}
+	if preference.QuietHoursStart != nil && preference.QuietHoursEnd != nil {
+		res.QuietHours = &dto.QuietHours{
+			Start:    *preference.QuietHoursStart,
+			End:      *preference.QuietHoursEnd,
+			Timezone: preference.Timezone,
+		}
+	}
+	return res
+}
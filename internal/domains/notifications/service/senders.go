@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	deviceService "ride-sharing/internal/domains/devices/service"
+)
+
+// PushSender delivers notifications to every registered device of the recipient
+type PushSender struct {
+	devices *deviceService.DeviceService
+}
+
+func NewPushSender(devices *deviceService.DeviceService) *PushSender {
+	return &PushSender{devices: devices}
+}
+
+func (s *PushSender) Send(ctx context.Context, notification Notification) error {
+	data := make(map[string]string, len(notification.Data)+1)
+	for key, value := range notification.Data {
+		data[key] = value
+	}
+	data["category"] = string(notification.Category)
+
+	s.devices.Push(ctx, notification.OwnerID, notification.OwnerType, notification.Title, notification.Body, data)
+	return nil
+}
+
+// InAppSender stores notifications in the recipient's inbox
+type InAppSender struct {
+	inbox *InboxService
+}
+
+func NewInAppSender(inbox *InboxService) *InAppSender {
+	return &InAppSender{inbox: inbox}
+}
+
+func (s *InAppSender) Send(ctx context.Context, notification Notification) error {
+	return s.inbox.Add(ctx, notification)
+}
@@ -25,4 +25,18 @@ type Rider struct {
 	TotalTrips        int       `gorm:"default:0"`
 	OnlineStatus      bool      `gorm:"default:false"`
 	PasswordChangedAt *time.Time
+	// SuspendedAt is set while the account is suspended
+	SuspendedAt *time.Time
+}
+
+func (Rider) TableName() string {
+	return "riders"
+}
+
+func (r *Rider) GetPasswordChangedAt() *time.Time {
+	return r.PasswordChangedAt
+}
+
+func (r *Rider) GetSuspendedAt() *time.Time {
+	return r.SuspendedAt
 }
@@ -1,20 +1,24 @@
 package provider
 
-// import (
-// 	"context"
+import (
+	"context"
+	"fmt"
 
-// 	"ride-sharing/internal/domains/riders/repository"
-// 	"ride-sharing/internal/pkg/auth"
-// )
+	"ride-sharing/internal/domains/riders/repository"
+	"ride-sharing/internal/pkg/auth"
+)
 
-// type UserProvider struct {
-// 	repo repository.UserRepository
-// }
+type RiderProvider struct {
+	repo repository.RiderRepository
+}
 
-// func NewUserProvider(repo repository.UserRepository) auth.UserProvider {
-// 	return &UserProvider{repo: repo}
-// }
+func NewRiderProvider(repo repository.RiderRepository) auth.UserProvider {
+	return &RiderProvider{repo: repo}
+}
 
-// func (p *UserProvider) GetByID(ctx context.Context, id string) (interface{}, error) {
-// 	return p.repo.GetByID(ctx, id)
-// }
+func (p *RiderProvider) GetByID(ctx context.Context, id string, userType auth.UserType) (interface{}, error) {
+	if userType != auth.UserTypeRider {
+		return nil, fmt.Errorf("invalid user type: %s", userType)
+	}
+	return p.repo.GetByID(ctx, id)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"ride-sharing/internal/domains/riders/models"
+	customErrors "ride-sharing/internal/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RiderRepository interface {
+	GetByID(ctx context.Context, id string) (*models.Rider, error)
+	GetByEmail(ctx context.Context, email string) (*models.Rider, error)
+	ChangePassword(ctx context.Context, rider *models.Rider, hashedPassword string) error
+	RevokeSessions(ctx context.Context, rider *models.Rider) error
+	SetSuspended(ctx context.Context, rider *models.Rider, suspended bool) error
+}
+
+type riderRepository struct {
+	db *gorm.DB
+}
+
+func NewRiderRepository(db *gorm.DB) RiderRepository {
+	return &riderRepository{db: db}
+}
+
+func (r *riderRepository) GetByID(ctx context.Context, id string) (*models.Rider, error) {
+	var rider models.Rider
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rider).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.NewNotFoundError("rider not found")
+		}
+		return nil, customErrors.NewInternalError(err)
+	}
+
+	return &rider, nil
+}
+
+func (r *riderRepository) GetByEmail(ctx context.Context, email string) (*models.Rider, error) {
+	var rider models.Rider
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&rider).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rider, nil
+}
+
+func (r *riderRepository) ChangePassword(ctx context.Context, rider *models.Rider, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(rider).Updates(map[string]interface{}{
+		"password":            hashedPassword,
+		"password_changed_at": time.Now(),
+	}).Error
+}
+
+// RevokeSessions moves password_changed_at forward, which invalidates every token issued before now
+func (r *riderRepository) RevokeSessions(ctx context.Context, rider *models.Rider) error {
+	return r.db.WithContext(ctx).Model(rider).Update("password_changed_at", time.Now()).Error
+}
+
+// SetSuspended suspends the account or lifts its suspension
+func (r *riderRepository) SetSuspended(ctx context.Context, rider *models.Rider, suspended bool) error {
+	var suspendedAt *time.Time
+	if suspended {
+		now := time.Now()
+		suspendedAt = &now
+	}
+	if err := r.db.WithContext(ctx).Model(rider).Update("suspended_at", suspendedAt).Error; err != nil {
+		return err
+	}
+	rider.SuspendedAt = suspendedAt
+	return nil
+}
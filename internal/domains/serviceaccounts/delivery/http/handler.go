@@ -0,0 +1,189 @@
+package http
+
+import (
+	"net/http"
+
+	"ride-sharing/internal/domains/serviceaccounts/dto"
+	"ride-sharing/internal/domains/serviceaccounts/models"
+	"ride-sharing/internal/domains/serviceaccounts/service"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+	"ride-sharing/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceAccountHandler struct {
+	service *service.ServiceAccountService
+}
+
+func NewServiceAccountHandler(service *service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{service: service}
+}
+
+// Create Service Account godoc
+// @Summary      Create a service account
+// @Description  Create a service account for a partner integration
+// @Tags         service-accounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.CreateServiceAccountRequest  true  "Service account data"
+// @Success      201      {object}  response.SuccessResponse{data=dto.ServiceAccountResponse}  "Service account created"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/service-accounts [post]
+func (h *ServiceAccountHandler) CreateAccount(c *gin.Context) {
+	var req dto.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.CreateAccount(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "service account created", res, nil)
+}
+
+// List Service Accounts godoc
+// @Summary      List service accounts
+// @Description  List service accounts with their API keys
+// @Tags         service-accounts
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200      {object}  response.SuccessResponse{data=[]dto.ServiceAccountResponse}  "Service accounts fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "Forbidden"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/service-accounts [get]
+func (h *ServiceAccountHandler) ListAccounts(c *gin.Context) {
+	res, err := h.service.ListAccounts(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "service accounts fetched", res, nil)
+}
+
+// Create API Key godoc
+// @Summary      Create an API key
+// @Description  Issue a new API key for a service account. The key is only shown once.
+// @Tags         service-accounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                   true  "Service account ID"
+// @Param        request  body  dto.CreateAPIKeyRequest  true  "API key data"
+// @Success      201      {object}  response.SuccessResponse{data=dto.APIKeySecretResponse}  "API key created"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      404      {object}  response.ErrorResponse  "Service account not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/service-accounts/{id}/keys [post]
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.CreateKey(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "api key created", res, nil)
+}
+
+// Rotate API Key godoc
+// @Summary      Rotate an API key
+// @Description  Issue a replacement key; the old key keeps working for the grace period, or until its own expiry if that is sooner
+// @Tags         service-accounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                   true  "Service account ID"
+// @Param        keyId    path  string                   true  "API key ID"
+// @Param        request  body  dto.RotateAPIKeyRequest  true  "Rotation options"
+// @Success      201      {object}  response.SuccessResponse{data=dto.APIKeySecretResponse}  "API key rotated"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      404      {object}  response.ErrorResponse  "API key not found"
+// @Failure      409      {object}  response.ErrorResponse  "API key already revoked"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/service-accounts/{id}/keys/{keyId}/rotate [post]
+func (h *ServiceAccountHandler) RotateKey(c *gin.Context) {
+	var req dto.RotateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.RotateKey(c.Request.Context(), c.Param("id"), c.Param("keyId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "api key rotated", res, nil)
+}
+
+// Revoke API Key godoc
+// @Summary      Revoke an API key
+// @Description  Revoke an API key immediately
+// @Tags         service-accounts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Service account ID"
+// @Param        keyId    path  string  true  "API key ID"
+// @Success      200      {object}  response.SuccessResponse{data=bool}  "API key revoked"
+// @Failure      404      {object}  response.ErrorResponse  "API key not found"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /admin/service-accounts/{id}/keys/{keyId} [delete]
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	res, err := h.service.RevokeKey(c.Request.Context(), c.Param("id"), c.Param("keyId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "api key revoked", res, nil)
+}
+
+// Service Account Identity godoc
+// @Summary      Current service account
+// @Description  Describe the service account and scopes behind the API key used. Requires the account:read scope.
+// @Tags         partner
+// @Produce      json
+// @Param        X-API-Key  header  string  true  "API key"
+// @Success      200      {object}  response.SuccessResponse{data=dto.ServiceAccountIdentityResponse}  "Identity fetched"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  response.ErrorResponse  "API key missing required scope"
+// @Failure      429      {object}  response.ErrorResponse  "Rate limit exceeded"
+// @Router       /partner/me [get]
+func (h *ServiceAccountHandler) Me(c *gin.Context) {
+	identity, exists := c.Get("apiKeyIdentity")
+	if !exists {
+		response.Error(c, errors.NewUnauthorizedError("api key identity not found in context"))
+		return
+	}
+	apiKeyIdentity := identity.(*auth.APIKeyIdentity)
+	account := apiKeyIdentity.Account.(*models.ServiceAccount)
+
+	response.Success(c, http.StatusOK, "identity fetched", dto.ServiceAccountIdentityResponse{
+		ServiceAccountID: apiKeyIdentity.ServiceAccountID,
+		Name:             account.Name,
+		KeyID:            apiKeyIdentity.KeyID,
+		Scopes:           apiKeyIdentity.Scopes,
+	}, nil)
+}
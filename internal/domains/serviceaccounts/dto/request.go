@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name               string   `json:"name" binding:"required,max=100"`
+	Scopes             []string `json:"scopes" binding:"required,min=1,dive,oneof=rides:read rides:write users:read account:read auth:introspect"`
+	RateLimitPerMinute int      `json:"rate_limit_per_minute" binding:"omitempty,min=1,max=10000"`
+	ExpiresInDays      int      `json:"expires_in_days" binding:"omitempty,min=1,max=730"`
+}
+
+type RotateAPIKeyRequest struct {
+	// How long the old key keeps working so partners can roll the new one out
+	GracePeriodMinutes int `json:"grace_period_minutes" binding:"omitempty,min=0,max=10080"`
+}
+
+type APIKeyResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name"`
+	Prefix             string     `json:"prefix"`
+	Scopes             []string   `json:"scopes"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	LastUsedAt         *time.Time `json:"last_used_at"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	RevokedAt          *time.Time `json:"revoked_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// APIKeySecretResponse is only returned when a key is created or rotated
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type ServiceAccountResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Active      bool             `json:"active"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Keys        []APIKeyResponse `json:"keys"`
+}
+
+type ServiceAccountIdentityResponse struct {
+	ServiceAccountID string   `json:"service_account_id"`
+	Name             string   `json:"name"`
+	KeyID            string   `json:"key_id"`
+	Scopes           []string `json:"scopes"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	CommonModels "ride-sharing/internal/pkg/models" // Import the common model package
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccount is a non-interactive identity used by partner integrations
+type ServiceAccount struct {
+	CommonModels.Common `swaggerignore:"true"`
+	Name                string `gorm:"unique;not null"`
+	Description         string
+	Active              bool     `gorm:"default:true"`
+	APIKeys             []APIKey `gorm:"foreignKey:ServiceAccountID"`
+}
+
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+type APIKey struct {
+	CommonModels.Common `swaggerignore:"true"`
+	ServiceAccountID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name                string    `gorm:"not null"`
+	Prefix              string    `gorm:"uniqueIndex;not null"`
+	KeyHash             string    `gorm:"not null"`
+	Scopes              []string  `gorm:"serializer:json;type:jsonb;not null"`
+	RateLimitPerMinute  int       `gorm:"not null;default:60"`
+	LastUsedAt          *time.Time
+	ExpiresAt           *time.Time
+	RevokedAt           *time.Time
+	RotatedFromID       *uuid.UUID `gorm:"type:uuid"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsUsable reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"ride-sharing/internal/domains/serviceaccounts/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ServiceAccountRepository interface {
+	CreateAccount(ctx context.Context, account *models.ServiceAccount) error
+	GetAccountByID(ctx context.Context, id string) (*models.ServiceAccount, error)
+	ListAccounts(ctx context.Context) ([]models.ServiceAccount, error)
+	CreateKey(ctx context.Context, key *models.APIKey) error
+	GetKey(ctx context.Context, accountID, keyID string) (*models.APIKey, error)
+	GetKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	RotateKey(ctx context.Context, oldKey *models.APIKey, oldExpiresAt time.Time, newKey *models.APIKey) error
+	RevokeKey(ctx context.Context, key *models.APIKey) error
+	TouchKey(ctx context.Context, key *models.APIKey, usedAt time.Time) error
+}
+
+type serviceAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountRepository(db *gorm.DB) ServiceAccountRepository {
+	return &serviceAccountRepository{db: db}
+}
+
+func (r *serviceAccountRepository) CreateAccount(ctx context.Context, account *models.ServiceAccount) error {
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+func (r *serviceAccountRepository) GetAccountByID(ctx context.Context, id string) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) ListAccounts(ctx context.Context) ([]models.ServiceAccount, error) {
+	var accounts []models.ServiceAccount
+	if err := r.db.WithContext(ctx).
+		Preload("APIKeys", func(db *gorm.DB) *gorm.DB { return db.Order("created_at DESC") }).
+		Order("created_at DESC").
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *serviceAccountRepository) CreateKey(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *serviceAccountRepository) GetKey(ctx context.Context, accountID, keyID string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("id = ? AND service_account_id = ?", keyID, accountID).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *serviceAccountRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RotateKey stores the replacement key and schedules the old one to expire, atomically
+func (r *serviceAccountRepository) RotateKey(ctx context.Context, oldKey *models.APIKey, oldExpiresAt time.Time, newKey *models.APIKey) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newKey).Error; err != nil {
+			return err
+		}
+		// Leave the old key alone if it was revoked or set to expire sooner in the meantime
+		return tx.Model(oldKey).
+			Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", oldExpiresAt).
+			Update("expires_at", oldExpiresAt).Error
+	})
+}
+
+func (r *serviceAccountRepository) RevokeKey(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Model(key).Update("revoked_at", time.Now()).Error
+}
+
+func (r *serviceAccountRepository) TouchKey(ctx context.Context, key *models.APIKey, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(key).UpdateColumn("last_used_at", usedAt).Error
+}
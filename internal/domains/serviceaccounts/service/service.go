@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"log"
+	"ride-sharing/internal/domains/serviceaccounts/dto"
+	"ride-sharing/internal/domains/serviceaccounts/models"
+	"ride-sharing/internal/domains/serviceaccounts/repository"
+	"ride-sharing/internal/pkg/apikey"
+	"ride-sharing/internal/pkg/auth"
+	customError "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/redis"
+	"time"
+)
+
+const (
+	defaultRateLimitPerMinute = 60
+	// lastUsedResolution limits how often last_used_at is written for a busy key
+	lastUsedResolution = time.Minute
+)
+
+type ServiceAccountService struct {
+	repo        repository.ServiceAccountRepository
+	rateLimiter *redis.RateLimiter
+}
+
+func NewServiceAccountService(repo repository.ServiceAccountRepository, rateLimiter *redis.RateLimiter) *ServiceAccountService {
+	return &ServiceAccountService{
+		repo:        repo,
+		rateLimiter: rateLimiter,
+	}
+}
+
+func (s *ServiceAccountService) CreateAccount(ctx context.Context, req dto.CreateServiceAccountRequest) (*dto.ServiceAccountResponse, *customError.AppError) {
+	account := &models.ServiceAccount{
+		Name:        req.Name,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := s.repo.CreateAccount(ctx, account); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res := toServiceAccountResponse(account)
+	return &res, nil
+}
+
+func (s *ServiceAccountService) ListAccounts(ctx context.Context) ([]dto.ServiceAccountResponse, *customError.AppError) {
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res := make([]dto.ServiceAccountResponse, 0, len(accounts))
+	for i := range accounts {
+		res = append(res, toServiceAccountResponse(&accounts[i]))
+	}
+	return res, nil
+}
+
+// CreateKey issues a new API key. The raw key is only returned here and cannot be retrieved later.
+func (s *ServiceAccountService) CreateKey(ctx context.Context, accountID string, req dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, *customError.AppError) {
+	account, err := s.repo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if account == nil {
+		return nil, customError.NewNotFoundError("service account not found")
+	}
+
+	raw, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	key := &models.APIKey{
+		ServiceAccountID:   account.ID,
+		Name:               req.Name,
+		Prefix:             prefix,
+		KeyHash:            hash,
+		Scopes:             req.Scopes,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+	}
+	if key.RateLimitPerMinute == 0 {
+		key.RateLimitPerMinute = defaultRateLimitPerMinute
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	return &dto.APIKeySecretResponse{APIKeyResponse: toAPIKeyResponse(key), Key: raw}, nil
+}
+
+// RotateKey issues a replacement with the same scopes and limits and lets the old
+// key keep working for the grace period.
+func (s *ServiceAccountService) RotateKey(ctx context.Context, accountID, keyID string, req dto.RotateAPIKeyRequest) (*dto.APIKeySecretResponse, *customError.AppError) {
+	oldKey, err := s.repo.GetKey(ctx, accountID, keyID)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if oldKey == nil {
+		return nil, customError.NewNotFoundError("api key not found")
+	}
+	if !oldKey.IsUsable(time.Now()) {
+		return nil, customError.NewConflictError("api key is already revoked or expired")
+	}
+
+	raw, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	newKey := &models.APIKey{
+		ServiceAccountID:   oldKey.ServiceAccountID,
+		Name:               oldKey.Name,
+		Prefix:             prefix,
+		KeyHash:            hash,
+		Scopes:             oldKey.Scopes,
+		RateLimitPerMinute: oldKey.RateLimitPerMinute,
+		ExpiresAt:          oldKey.ExpiresAt,
+		RotatedFromID:      &oldKey.ID,
+	}
+
+	// The grace period only ever shortens the old key's life, never extends it
+	oldExpiresAt := time.Now().Add(time.Duration(req.GracePeriodMinutes) * time.Minute)
+	if oldKey.ExpiresAt != nil && oldKey.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *oldKey.ExpiresAt
+	}
+	if err := s.repo.RotateKey(ctx, oldKey, oldExpiresAt, newKey); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	return &dto.APIKeySecretResponse{APIKeyResponse: toAPIKeyResponse(newKey), Key: raw}, nil
+}
+
+func (s *ServiceAccountService) RevokeKey(ctx context.Context, accountID, keyID string) (bool, *customError.AppError) {
+	key, err := s.repo.GetKey(ctx, accountID, keyID)
+	if err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	if key == nil {
+		return false, customError.NewNotFoundError("api key not found")
+	}
+	if key.RevokedAt != nil {
+		return true, nil
+	}
+
+	if err := s.repo.RevokeKey(ctx, key); err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	return true, nil
+}
+
+// AuthenticateAPIKey implements auth.APIKeyAuthenticator
+func (s *ServiceAccountService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*auth.APIKeyIdentity, error) {
+	prefix, ok := apikey.Prefix(rawKey)
+	if !ok {
+		return nil, customError.NewUnauthorizedError("invalid api key")
+	}
+
+	key, err := s.repo.GetKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if key == nil || !apikey.Matches(rawKey, key.KeyHash) {
+		return nil, customError.NewUnauthorizedError("invalid api key")
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, customError.NewUnauthorizedError("api key revoked or expired")
+	}
+
+	account, err := s.repo.GetAccountByID(ctx, key.ServiceAccountID.String())
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if account == nil || !account.Active {
+		return nil, customError.NewUnauthorizedError("service account disabled")
+	}
+
+	allowed, err := s.rateLimiter.Allow(ctx, "apikey:"+key.ID.String(), key.RateLimitPerMinute, time.Minute)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if !allowed {
+		return nil, customError.NewTooManyRequestsError("api key rate limit exceeded")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedResolution {
+		if err := s.repo.TouchKey(ctx, key, now); err != nil {
+			log.Printf("Failed to update api key last used time: %v", err)
+		}
+	}
+
+	return &auth.APIKeyIdentity{
+		ServiceAccountID: account.ID.String(),
+		KeyID:            key.ID.String(),
+		Scopes:           key.Scopes,
+		Account:          account,
+	}, nil
+}
+
+func toServiceAccountResponse(account *models.ServiceAccount) dto.ServiceAccountResponse {
+	keys := make([]dto.APIKeyResponse, 0, len(account.APIKeys))
+	for i := range account.APIKeys {
+		keys = append(keys, toAPIKeyResponse(&account.APIKeys[i]))
+	}
+	return dto.ServiceAccountResponse{
+		ID:          account.ID,
+		Name:        account.Name,
+		Description: account.Description,
+		Active:      account.Active,
+		CreatedAt:   account.CreatedAt,
+		Keys:        keys,
+	}
+}
+
+func toAPIKeyResponse(key *models.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:                 key.ID,
+		Name:               key.Name,
+		Prefix:             key.Prefix,
+		Scopes:             key.Scopes,
+		RateLimitPerMinute: key.RateLimitPerMinute,
+		LastUsedAt:         key.LastUsedAt,
+		ExpiresAt:          key.ExpiresAt,
+		RevokedAt:          key.RevokedAt,
+		CreatedAt:          key.CreatedAt,
+	}
+}
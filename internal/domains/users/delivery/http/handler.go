@@ -27,9 +27,11 @@ func NewUserHandler(service *service.UserService) *UserHandler {
 // @Accept       json
 // @Produce      json
 // @Param        request  body  dto.RegisterRequest  true  "User registration data"
+// @Param        Idempotency-Key  header  string  false  "Client generated key; retries with the same key replay the first response"
 // @Success      201      {object}  response.SuccessResponse{dto.UserResponse}  "User registered successfully"
 // @Failure      400      {object}  response.ErrorResponse  "Validation error"
-// @Failure      409      {object}  response.ErrorResponse  "User already exists"
+// @Failure      409      {object}  response.ErrorResponse  "User already exists, or a request with the same idempotency key is in progress"
+// @Failure      422      {object}  response.ErrorResponse  "Idempotency key reused with a different request"
 // @Failure      500      {object}  response.ErrorResponse  "Internal server error"
 // @Router       /users/register [post]
 func (h *UserHandler) Register(c *gin.Context) {
@@ -51,7 +53,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary      Login a user
-// @Description  Authenticate user and return access & refresh tokens
+// @Description  Authenticate user and return access & refresh tokens. When the password has expired only a short-lived access token for change-password is returned, with password_expired set.
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -59,6 +61,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Success      200      {object}  response.SuccessResponse{data=dto.LoginResponse}  "Login successful"
 // @Failure      400      {object}  response.ErrorResponse  "Validation error"
 // @Failure      401      {object}  response.ErrorResponse  "Invalid credentials"
+// @Failure      403      {object}  response.ErrorResponse  "Account suspended"
 // @Failure      500      {object}  response.ErrorResponse  "Internal server error"
 // @Router       /users/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
@@ -80,13 +83,14 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // Refresh godoc
 // @Summary      Refresh access token
-// @Description  Get new access token using refresh token
+// @Description  Get new access token using refresh token. When the password has expired the token only allows change-password.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        request  body  dto.RefreshRequest  true  "Refresh token"
 // @Success      200      {object}  response.SuccessResponse{data=dto.RefreshResponse}  "Token refreshed successfully"
 // @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      403      {object}  response.ErrorResponse  "Account suspended"
 // @Failure      500      {object}  response.ErrorResponse  "Internal server error"
 // @Router       /users/refresh [post]
 func (h *UserHandler) Refresh(c *gin.Context) {
@@ -108,7 +112,7 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 
 // Change Password godoc
 // @Summary      Change user password
-// @Description  Change password for authenticated user
+// @Description  Change password for authenticated user. Also accepts the restricted token issued for an expired password.
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -253,3 +257,112 @@ func (h *UserHandler) VerifyEmail(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "User verified", nil, nil)
 }
+
+// Request Login Code godoc
+// @Summary      Request passwordless login code
+// @Description  Send a one-time login code to the user's email or phone, bound to the requesting device
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.LoginCodeRequest  true  "Login code request"
+// @Success      202      {object}  response.SuccessResponse{data=bool}  "Login code sent"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      403      {object}  response.ErrorResponse  "Login codes not available for this account"
+// @Failure      404      {object}  response.ErrorResponse  "User not found"
+// @Failure      409      {object}  response.ErrorResponse  "Login code already sent"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /users/login-code [post]
+func (h *UserHandler) RequestLoginCode(c *gin.Context) {
+	var req dto.LoginCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.RequestLoginCode(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "login code sent", res, nil)
+}
+
+// Verify Login Code godoc
+// @Summary      Login with a one-time code
+// @Description  Verify a passwordless login code and return access & refresh tokens
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.VerifyLoginCodeRequest  true  "Login code verification"
+// @Success      200      {object}  response.SuccessResponse{data=dto.LoginResponse}  "Login successful"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      404      {object}  response.ErrorResponse  "User not found"
+// @Failure      429      {object}  response.ErrorResponse  "Too many invalid attempts"
+// @Failure      403      {object}  response.ErrorResponse  "Account suspended"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /users/login-code/verify [post]
+func (h *UserHandler) VerifyLoginCode(c *gin.Context) {
+	var req dto.VerifyLoginCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	res, err := h.service.VerifyLoginCode(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "login successful", res, nil)
+}
+
+// Password Policy godoc
+// @Summary      Password policy
+// @Description  Get the active password rules so clients can show hints before submitting
+// @Tags         users
+// @Produce      json
+// @Success      200      {object}  response.SuccessResponse{data=password.Rules}  "Password policy fetched"
+// @Router       /users/password-policy [get]
+func (h *UserHandler) PasswordPolicy(c *gin.Context) {
+	response.Success(c, http.StatusOK, "password policy fetched", h.service.PasswordPolicy(), nil)
+}
+
+// Update locale godoc
+// @Summary      Update locale
+// @Description  Set the language notifications are sent in
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.UpdateLocaleRequest  true  "Locale"
+// @Success      200      {object}  response.SuccessResponse{data=dto.UserResponse}  "Locale updated"
+// @Failure      400      {object}  response.ErrorResponse  "Validation error"
+// @Failure      401      {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  response.ErrorResponse  "Internal server error"
+// @Router       /users/locale [put]
+func (h *UserHandler) UpdateLocale(c *gin.Context) {
+	var req dto.UpdateLocaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		details := validation.ProcessValidationError(err)
+		response.Error(c, errors.NewValidationError("invalid request body", details))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, errors.NewUnauthorizedError("user ID not found in context"))
+		return
+	}
+
+	res, err := h.service.UpdateLocale(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "locale updated", res, nil)
+}
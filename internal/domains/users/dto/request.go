@@ -12,6 +12,7 @@ type RegisterRequest struct {
 	FullName        string `json:"full_name" binding:"required"`
 	Phone           string `json:"phone" binding:"required,e164"`
 	Address         string `json:"address" binding:"required"`
+	Locale          string `json:"locale" binding:"omitempty,bcp47_language_tag" example:"en"`
 }
 
 type UserResponse struct {
@@ -19,11 +20,16 @@ type UserResponse struct {
 	Email    string    `json:"email"`
 	FullName string    `json:"full_name"`
 	Phone    string    `json:"phone"`
+	Locale   string    `json:"locale"`
+}
+
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale" binding:"required,bcp47_language_tag" example:"ne"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,strongpassword"`
+	Password string `json:"password" binding:"required"`
 }
 
 type RefreshRequest struct {
@@ -32,16 +38,21 @@ type RefreshRequest struct {
 
 type RefreshResponse struct {
 	AccessToken string `json:"access_token"`
+	// PasswordExpired means AccessToken only allows changing the password
+	PasswordExpired bool `json:"password_expired,omitempty"`
 }
 
 type LoginResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
-	User         UserResponse `json:"user"`
+	AccessToken string `json:"access_token"`
+	// RefreshToken is left out when the password has expired
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// PasswordExpired means AccessToken only allows changing the password
+	PasswordExpired bool         `json:"password_expired,omitempty"`
+	User            UserResponse `json:"user"`
 }
 
 type ChangePasswordRequest struct {
-	CurrentPassword string `json:"current_password" binding:"required,strongpassword"`
+	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,strongpassword"`
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
 }
@@ -61,3 +72,16 @@ type VerifyEmailRequest struct {
 	Email string `json:"email" binding:"required"`
 	Otp   string `json:"otp" binding:"required,otpvalidation"`
 }
+
+type LoginCodeRequest struct {
+	Email    string `json:"email" binding:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" binding:"required_without=Email,omitempty,e164"`
+	DeviceID string `json:"device_id" binding:"required,max=128"`
+}
+
+type VerifyLoginCodeRequest struct {
+	Email    string `json:"email" binding:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" binding:"required_without=Email,omitempty,e164"`
+	DeviceID string `json:"device_id" binding:"required,max=128"`
+	Otp      string `json:"otp" binding:"required,otpvalidation"`
+}
@@ -13,9 +13,20 @@ type User struct {
 	Email               string `gorm:"unique;not null"`
 	Password            string `gorm:"not null"`
 	Active              bool   `gorm:"default:false"`
+	Locale              string `gorm:"not null;default:en"`
 	PasswordChangedAt   *time.Time
+	// SuspendedAt is set while the account is suspended
+	SuspendedAt *time.Time
 }
 
 func (User) TableName() string {
 	return "users"
 }
+
+func (u *User) GetPasswordChangedAt() *time.Time {
+	return u.PasswordChangedAt
+}
+
+func (u *User) GetSuspendedAt() *time.Time {
+	return u.SuspendedAt
+}
@@ -5,19 +5,26 @@ import (
 	"errors"
 	"ride-sharing/internal/domains/users/models"
 	customErrors "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/outbox"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, user *models.User) error
+	Create(ctx context.Context, user *models.User, events ...outbox.Message) error
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByPhone(ctx context.Context, phone string) (*models.User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	ExistsByPhone(ctx context.Context, phone string) (bool, error)
-	ChangePassword(ctx context.Context, user *models.User, hashedPassword string) (bool, error)
+	ChangePassword(ctx context.Context, user *models.User, hashedPassword string, historySize int, events ...outbox.Message) (bool, error)
+	UpdatePasswordHash(ctx context.Context, user *models.User, hashedPassword string) error
+	GetPasswordHistory(ctx context.Context, userID string, limit int) ([]models.PasswordHistory, error)
 	GetByID(ctx context.Context, id string) (*models.User, error)
-	ActivateUserByEmail(ctx context.Context, user *models.User) (bool, error)
+	ActivateUserByEmail(ctx context.Context, user *models.User, events ...outbox.Message) (bool, error)
+	UpdateLocale(ctx context.Context, user *models.User, locale string) error
+	RevokeSessions(ctx context.Context, user *models.User) error
+	SetSuspended(ctx context.Context, user *models.User, suspended bool) error
 }
 
 type userRepository struct {
@@ -28,11 +35,18 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
-		return err
-	}
-	return nil
+// Create inserts the user and its first password history entry. Any events are written
+// to the outbox in the same transaction.
+func (r *userRepository) Create(ctx context.Context, user *models.User, events ...outbox.Message) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.PasswordHistory{UserID: user.ID, Password: user.Password}).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, events...)
+	})
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -46,6 +60,17 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	return &user, nil
 }
 
+func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("phone = ?", phone).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	var count int64
 	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
@@ -62,22 +87,66 @@ func (r *userRepository) ExistsByPhone(ctx context.Context, phone string) (bool,
 	return count > 0, nil
 }
 
-func (r *userRepository) ChangePassword(ctx context.Context, user *models.User, hashedPassword string) (bool, error) {
-	now := time.Now()
-	result := r.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
-		"password":            hashedPassword,
-		"password_changed_at": now,
-	})
-
-	if result.Error != nil {
-		return false, result.Error
+// ChangePassword updates the password and records it in the password history,
+// keeping at most historySize entries per user. Events are only enqueued when the password was updated.
+func (r *userRepository) ChangePassword(ctx context.Context, user *models.User, hashedPassword string, historySize int, events ...outbox.Message) (bool, error) {
+	if historySize < 1 {
+		historySize = 1
 	}
 
-	if result.RowsAffected == 0 {
-		return false, nil
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(user).Updates(map[string]interface{}{
+			"password":            hashedPassword,
+			"password_changed_at": now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+
+		if err := tx.Create(&models.PasswordHistory{UserID: user.ID, Password: hashedPassword}).Error; err != nil {
+			return err
+		}
+
+		// Drop entries older than the most recent historySize passwords
+		recent := tx.Model(&models.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", user.ID).
+			Order("created_at DESC").
+			Limit(historySize)
+		if err := tx.Where("user_id = ? AND id NOT IN (?)", user.ID, recent).Delete(&models.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, events...)
+	})
+	if err != nil {
+		return false, err
 	}
 
-	return true, nil
+	return updated, nil
+}
+
+// UpdatePasswordHash replaces the stored hash of the same password, e.g. after an algorithm upgrade.
+// Unlike ChangePassword it leaves password_changed_at untouched so issued tokens stay valid.
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, user *models.User, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(user).Update("password", hashedPassword).Error
+}
+
+func (r *userRepository) GetPasswordHistory(ctx context.Context, userID string, limit int) ([]models.PasswordHistory, error) {
+	var history []models.PasswordHistory
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
@@ -94,12 +163,41 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User,
 	return &user, nil
 }
 
-func (r *userRepository) ActivateUserByEmail(ctx context.Context, user *models.User) (bool, error) {
+func (r *userRepository) ActivateUserByEmail(ctx context.Context, user *models.User, events ...outbox.Message) (bool, error) {
 	user.Active = true
 
-	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, events...)
+	})
+	if err != nil {
 		return false, customErrors.NewInternalError(err)
 	}
 
 	return true, nil
 }
+
+func (r *userRepository) UpdateLocale(ctx context.Context, user *models.User, locale string) error {
+	return r.db.WithContext(ctx).Model(user).Update("locale", locale).Error
+}
+
+// RevokeSessions moves password_changed_at forward, which invalidates every token issued before now
+func (r *userRepository) RevokeSessions(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Model(user).Update("password_changed_at", time.Now()).Error
+}
+
+// SetSuspended suspends the account or lifts its suspension
+func (r *userRepository) SetSuspended(ctx context.Context, user *models.User, suspended bool) error {
+	var suspendedAt *time.Time
+	if suspended {
+		now := time.Now()
+		suspendedAt = &now
+	}
+	if err := r.db.WithContext(ctx).Model(user).Update("suspended_at", suspendedAt).Error; err != nil {
+		return err
+	}
+	user.SuspendedAt = suspendedAt
+	return nil
+}
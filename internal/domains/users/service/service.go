@@ -4,17 +4,26 @@ import (
 	"context"
 	"errors"
 	"log"
+	featureFlagService "ride-sharing/internal/domains/featureflags/service"
+	notificationModels "ride-sharing/internal/domains/notifications/models"
+	notificationService "ride-sharing/internal/domains/notifications/service"
 	"ride-sharing/internal/domains/users/dto"
 	"ride-sharing/internal/domains/users/models"
 	"ride-sharing/internal/domains/users/repository"
 	"ride-sharing/internal/pkg/auth"
 	"ride-sharing/internal/pkg/constants"
 	customError "ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/events"
 	email "ride-sharing/internal/pkg/grpcclient"
+	"ride-sharing/internal/pkg/metrics"
+	commonModels "ride-sharing/internal/pkg/models"
 	"ride-sharing/internal/pkg/otp"
+	"ride-sharing/internal/pkg/outbox"
 	"ride-sharing/internal/pkg/password"
 	"ride-sharing/internal/pkg/redis"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type UserService struct {
@@ -23,15 +32,23 @@ type UserService struct {
 	OTPStore           *redis.OTPStore
 	notificationClient *email.NotificationClient
 	userProviders      map[auth.UserType]auth.UserProvider
+	passwordPolicy     *password.Policy
+	dispatcher         *notificationService.Dispatcher
+	featureFlags       *featureFlagService.FeatureFlagService
+	identities         auth.IdentityInvalidator
 }
 
-func NewUserService(repo repository.UserRepository, tokenService *auth.TokenService, otpStore *redis.OTPStore, notificationClient *email.NotificationClient, userProviders map[auth.UserType]auth.UserProvider) *UserService {
+func NewUserService(repo repository.UserRepository, tokenService *auth.TokenService, otpStore *redis.OTPStore, notificationClient *email.NotificationClient, userProviders map[auth.UserType]auth.UserProvider, passwordPolicy *password.Policy, dispatcher *notificationService.Dispatcher, featureFlags *featureFlagService.FeatureFlagService, identities auth.IdentityInvalidator) *UserService {
 	return &UserService{
 		repo:               repo,
 		tokenService:       tokenService,
 		OTPStore:           otpStore,
 		userProviders:      userProviders,
 		notificationClient: notificationClient,
+		passwordPolicy:     passwordPolicy,
+		dispatcher:         dispatcher,
+		featureFlags:       featureFlags,
+		identities:         identities,
 	}
 }
 
@@ -54,6 +71,10 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, customError.NewConflictError("phone number already exists")
 	}
 
+	if appErr := s.checkNewPassword(ctx, nil, req.Password, "password"); appErr != nil {
+		return nil, appErr
+	}
+
 	// Hash password
 	hashedPassword, err := password.HashPassword(req.Password)
 	if err != nil {
@@ -63,17 +84,30 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 	// Create user
 	current_time := time.Now()
 	user := &models.User{
+		Common:            commonModels.Common{ID: uuid.New()},
 		Email:             req.Email,
 		Password:          string(hashedPassword),
 		FullName:          req.FullName,
 		Phone:             req.Phone,
+		Locale:            req.Locale,
 		Active:            false,
 		PasswordChangedAt: &current_time,
 	}
 
-	if err := s.repo.Create(ctx, user); err != nil {
+	registered, err := events.New(ctx, events.UserRegistered, user.ID.String(), events.UserRegisteredData{
+		UserID:   user.ID.String(),
+		Email:    user.Email,
+		Phone:    user.Phone,
+		FullName: user.FullName,
+		UserType: string(auth.UserTypeUser),
+	})
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if err := s.repo.Create(ctx, user, registered); err != nil {
 		return nil, customError.NewInternalError(err)
 	}
+	metrics.RegistrationsTotal.Inc()
 	otp := otp.GenerateOTP()
 
 	if err := s.OTPStore.SetOTP(ctx, user.Email, otp, string(constants.OTPUserRegister)); err != nil {
@@ -85,7 +119,7 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, customError.NewInternalError(err)
 	}
 
-	if _, err := s.notificationClient.SendRegisterEmail(ctx, req.Email, otp); err != nil {
+	if _, err := s.notificationClient.SendRegisterEmail(ctx, req.Email, otp, user.Locale); err != nil {
 		return nil, customError.NewInternalError(err)
 	}
 	return &dto.UserResponse{
@@ -93,6 +127,7 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		Email:    user.Email,
 		FullName: user.FullName,
 		Phone:    user.Phone,
+		Locale:   user.Locale,
 	}, nil
 }
 
@@ -102,6 +137,7 @@ func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 		return nil, customError.NewInternalError(err) // Wrap the error
 	}
 	if user == nil {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "password", "failure").Inc()
 		return nil, customError.NewNotFoundError("user not found")
 	}
 
@@ -110,29 +146,145 @@ func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 		return nil, customError.NewInternalError(err)
 	}
 	if !match {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "password", "failure").Inc()
 		return nil, customError.NewUnauthorizedError("invalid credentials")
 	}
+	metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "password", "success").Inc()
 
-	accessToken, err := s.tokenService.GenerateAccessToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
+	// Transparently upgrade hashes produced by an outdated algorithm or parameters
+	if password.NeedsRehash(user.Password) {
+		if hashedPassword, err := password.HashPassword(req.Password); err != nil {
+			log.Printf("Failed to rehash password: %v", err)
+		} else if err := s.repo.UpdatePasswordHash(ctx, user, hashedPassword); err != nil {
+			log.Printf("Failed to store rehashed password: %v", err)
+		}
+	}
+
+	return s.generateLoginResponse(user)
+}
+
+// RequestLoginCode sends a one-time login code to the user's email or phone.
+// The code is bound to the device that requested it.
+func (s *UserService) RequestLoginCode(ctx context.Context, req dto.LoginCodeRequest) (bool, *customError.AppError) {
+	user, channel, to, appErr := s.findLoginCodeUser(ctx, req.Email, req.Phone)
+	if appErr != nil {
+		return false, appErr
+	}
+	subject := featureFlagService.Subject{UserID: user.ID.String(), UserType: string(auth.UserTypeUser)}
+	if !s.featureFlags.Enabled(ctx, featureFlagService.FlagPasswordlessLogin, subject) {
+		return false, customError.NewForbiddenError("login codes are not available for this account")
+	}
+
+	otp := otp.GenerateOTP()
+
+	if err := s.OTPStore.SetBoundOTP(ctx, user.ID.String(), otp, string(constants.OTPPasswordless), req.DeviceID); err != nil {
+		var conflictErr *customError.AppError
+		if errors.As(err, &conflictErr) && conflictErr.Type == customError.ErrorTypeConflict {
+			return false, conflictErr
+		}
+		return false, customError.NewInternalError(err)
+	}
+
+	if _, err := s.notificationClient.SendLoginCode(ctx, channel, to, otp, user.Locale); err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	return true, nil
+}
+
+// VerifyLoginCode exchanges a valid login code for the same tokens a password login returns.
+func (s *UserService) VerifyLoginCode(ctx context.Context, req dto.VerifyLoginCodeRequest) (*dto.LoginResponse, *customError.AppError) {
+	user, _, _, appErr := s.findLoginCodeUser(ctx, req.Email, req.Phone)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	valid, err := s.OTPStore.VerifyBoundOTP(ctx, user.ID.String(), req.Otp, string(constants.OTPPasswordless), req.DeviceID)
 	if err != nil {
+		var otpErr *customError.AppError
+		if errors.As(err, &otpErr) {
+			metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "login_code", "failure").Inc()
+			return nil, otpErr
+		}
 		return nil, customError.NewInternalError(err)
 	}
+	if !valid {
+		metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "login_code", "failure").Inc()
+		return nil, customError.NewVerificationError("invalid or expired OTP")
+	}
+	metrics.LoginsTotal.WithLabelValues(string(auth.UserTypeUser), "login_code", "success").Inc()
 
-	refreshToken, err := s.tokenService.GenerateRefreshToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
+	return s.generateLoginResponse(user)
+}
+
+// findLoginCodeUser resolves the user a login code is meant for along with the
+// channel and destination the code is delivered to.
+func (s *UserService) findLoginCodeUser(ctx context.Context, email, phone string) (*models.User, constants.LoginCodeChannel, string, *customError.AppError) {
+	var (
+		user    *models.User
+		err     error
+		channel constants.LoginCodeChannel
+	)
+
+	if email != "" {
+		user, err = s.repo.GetByEmail(ctx, email)
+		channel = constants.LoginCodeChannelEmail
+	} else {
+		user, err = s.repo.GetByPhone(ctx, phone)
+		channel = constants.LoginCodeChannelSMS
+	}
 	if err != nil {
-		return nil, customError.NewInternalError(err)
+		return nil, "", "", customError.NewInternalError(err)
+	}
+	if user == nil {
+		return nil, "", "", customError.NewNotFoundError("user not found")
 	}
 
-	return &dto.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	if channel == constants.LoginCodeChannelEmail {
+		return user, channel, user.Email, nil
+	}
+	return user, channel, user.Phone, nil
+}
+
+// generateLoginResponse issues a token pair, or only a password change token when the
+// password is past its maximum age so the user has to pick a new one before going on.
+func (s *UserService) generateLoginResponse(user *models.User) (*dto.LoginResponse, *customError.AppError) {
+	if user.SuspendedAt != nil {
+		return nil, customError.NewForbiddenError("account suspended")
+	}
+
+	res := &dto.LoginResponse{
 		User: dto.UserResponse{
 			ID:       user.ID,
 			Email:    user.Email,
 			FullName: user.FullName,
 			Phone:    user.Phone,
+			Locale:   user.Locale,
 		},
-	}, nil
+	}
+
+	if s.passwordPolicy.IsExpired(user.PasswordChangedAt) {
+		accessToken, err := s.tokenService.GeneratePasswordChangeToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
+		if err != nil {
+			return nil, customError.NewInternalError(err)
+		}
+		res.AccessToken = accessToken
+		res.PasswordExpired = true
+		return res, nil
+	}
+
+	accessToken, err := s.tokenService.GenerateAccessToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	refreshToken, err := s.tokenService.GenerateRefreshToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+
+	res.AccessToken = accessToken
+	res.RefreshToken = refreshToken
+	return res, nil
 }
 
 func (s *UserService) RefreshToken(ctx context.Context, req dto.RefreshRequest) (*dto.RefreshResponse, *customError.AppError) {
@@ -155,12 +307,24 @@ func (s *UserService) RefreshToken(ctx context.Context, req dto.RefreshRequest)
 	}
 
 	userData := user.(*models.User)
+	if userData.SuspendedAt != nil {
+		return nil, customError.NewForbiddenError("account suspended")
+	}
 
 	tokenPasswordChangedAt := time.Unix(0, refreshClaims.PasswordChangedAt)
 	if tokenPasswordChangedAt.Before(*userData.PasswordChangedAt) {
 		return nil, customError.NewUnauthorizedError("password changed - please login again")
 	}
 
+	// Refresh tokens issued before the password expired only get a password change token
+	if s.passwordPolicy.IsExpired(userData.PasswordChangedAt) {
+		accessToken, err := s.tokenService.GeneratePasswordChangeToken(userData.ID.String(), auth.UserTypeUser, userData.PasswordChangedAt)
+		if err != nil {
+			return nil, customError.NewInternalError(err)
+		}
+		return &dto.RefreshResponse{AccessToken: accessToken, PasswordExpired: true}, nil
+	}
+
 	accessToken, err := s.tokenService.GenerateAccessToken(userData.ID.String(), auth.UserTypeUser, userData.PasswordChangedAt)
 	if err != nil {
 		return nil, customError.NewInternalError(err)
@@ -189,15 +353,25 @@ func (s *UserService) ChangePassword(ctx context.Context, userID string, req dto
 		return nil, customError.NewVerificationError("incorrect current password")
 	}
 
+	if appErr := s.checkNewPassword(ctx, user, req.NewPassword, "new_password"); appErr != nil {
+		return nil, appErr
+	}
+
 	hashedPassword, err := password.HashPassword(req.NewPassword)
 	if err != nil {
 		return nil, customError.NewInternalError(err)
 	}
 
-	success, err := s.repo.ChangePassword(ctx, user, hashedPassword)
+	changed, err := passwordChangedEvent(ctx, user, "change")
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	success, err := s.repo.ChangePassword(ctx, user, hashedPassword, s.passwordPolicy.HistorySize(), changed)
 	if err != nil || !success {
 		return nil, customError.NewInternalError(err)
 	}
+	s.identities.Invalidate(ctx, auth.UserTypeUser, user.ID.String())
+	s.alertPasswordChanged(ctx, user)
 
 	accessToken, err := s.tokenService.GenerateAccessToken(user.ID.String(), auth.UserTypeUser, user.PasswordChangedAt)
 	if err != nil {
@@ -216,10 +390,53 @@ func (s *UserService) ChangePassword(ctx context.Context, userID string, req dto
 			Email:    user.Email,
 			FullName: user.FullName,
 			Phone:    user.Phone,
+			Locale:   user.Locale,
 		},
 	}, nil
 }
 
+// checkNewPassword enforces the policy rules that cannot be expressed as binding tags:
+// the breached password list and, for existing users, reuse of recent passwords.
+func (s *UserService) checkNewPassword(ctx context.Context, user *models.User, newPassword string, field string) *customError.AppError {
+	if s.passwordPolicy.IsBreached(newPassword) {
+		return customError.NewValidationError("invalid request body", map[string]string{
+			field: "Password has appeared in a data breach, choose a different one",
+		})
+	}
+
+	if user == nil || s.passwordPolicy.HistorySize() <= 0 {
+		return nil
+	}
+
+	history, err := s.repo.GetPasswordHistory(ctx, user.ID.String(), s.passwordPolicy.HistorySize())
+	if err != nil {
+		return customError.NewInternalError(err)
+	}
+
+	hashes := []string{user.Password}
+	for _, entry := range history {
+		hashes = append(hashes, entry.Password)
+	}
+
+	for _, hash := range hashes {
+		match, err := password.CheckPassword(newPassword, hash)
+		if err != nil {
+			return customError.NewInternalError(err)
+		}
+		if match {
+			return customError.NewValidationError("invalid request body", map[string]string{
+				field: "Password was used recently, choose a different one",
+			})
+		}
+	}
+	return nil
+}
+
+// PasswordPolicy returns the active password rules so clients can render hints
+func (s *UserService) PasswordPolicy() password.Rules {
+	return s.passwordPolicy.Rules()
+}
+
 func (s *UserService) ForgetPassword(ctx context.Context, req dto.ForgetPasswordRequest) (bool, *customError.AppError) {
 	user, err := s.repo.GetByEmail(ctx, req.Email)
 	if err != nil {
@@ -239,7 +456,7 @@ func (s *UserService) ForgetPassword(ctx context.Context, req dto.ForgetPassword
 		}
 		return false, customError.NewInternalError(err)
 	}
-	if _, err := s.notificationClient.SendForgetPasswordEmail(ctx, req.Email, otp); err != nil {
+	if _, err := s.notificationClient.SendForgetPasswordEmail(ctx, req.Email, otp, user.Locale); err != nil {
 		log.Printf("Failed to send forget-password email: %v", err)
 		// You can decide whether this should return a user-facing error or not
 	}
@@ -256,7 +473,22 @@ func (s *UserService) VerifyForgetPassword(ctx context.Context, req dto.ForgetPa
 		return false, customError.NewNotFoundError("user not found")
 	}
 
-	valid, err := s.OTPStore.VerifyAndDeleteOTP(ctx, req.Email, req.Otp, string(constants.OTPForgetPassword))
+	// The code is only consumed once the new password is accepted, so a rejected password
+	// does not force the user to request another one. It is still checked first, so the
+	// password history cannot be probed without it.
+	valid, err := s.OTPStore.CheckOTP(ctx, req.Email, req.Otp, string(constants.OTPForgetPassword))
+	if err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	if !valid {
+		return false, customError.NewVerificationError("invalid or expired OTP")
+	}
+
+	if appErr := s.checkNewPassword(ctx, user, req.Password, "password"); appErr != nil {
+		return false, appErr
+	}
+
+	valid, err = s.OTPStore.VerifyAndDeleteOTP(ctx, req.Email, req.Otp, string(constants.OTPForgetPassword))
 	if err != nil {
 		return false, customError.NewInternalError(err)
 	}
@@ -269,10 +501,16 @@ func (s *UserService) VerifyForgetPassword(ctx context.Context, req dto.ForgetPa
 		return false, customError.NewInternalError(err)
 	}
 
-	success, err := s.repo.ChangePassword(ctx, user, hashedPassword)
+	changed, err := passwordChangedEvent(ctx, user, "reset")
+	if err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	success, err := s.repo.ChangePassword(ctx, user, hashedPassword, s.passwordPolicy.HistorySize(), changed)
 	if err != nil || !success {
 		return false, customError.NewInternalError(err)
 	}
+	s.identities.Invalidate(ctx, auth.UserTypeUser, user.ID.String())
+	s.alertPasswordChanged(ctx, user)
 	// Remaining: send email
 	return true, nil
 }
@@ -289,8 +527,32 @@ func (s *UserService) UserProfile(ctx context.Context, UserId string) (*dto.User
 		ID:       user.ID,
 		Email:    user.Email,
 		FullName: user.FullName,
-		Phone:    user.Phone}, nil
+		Phone:    user.Phone,
+		Locale:   user.Locale}, nil
+
+}
+
+// UpdateLocale sets the language notifications are rendered in
+func (s *UserService) UpdateLocale(ctx context.Context, userID string, req dto.UpdateLocaleRequest) (*dto.UserResponse, *customError.AppError) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	if user == nil {
+		return nil, customError.NewNotFoundError("user not found")
+	}
 
+	if err := s.repo.UpdateLocale(ctx, user, req.Locale); err != nil {
+		return nil, customError.NewInternalError(err)
+	}
+	s.identities.Invalidate(ctx, auth.UserTypeUser, user.ID.String())
+	return &dto.UserResponse{
+		ID:       user.ID,
+		Email:    user.Email,
+		FullName: user.FullName,
+		Phone:    user.Phone,
+		Locale:   user.Locale,
+	}, nil
 }
 
 func (s *UserService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (bool, *customError.AppError) {
@@ -310,8 +572,37 @@ func (s *UserService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 		return false, customError.NewVerificationError("invalid or expired OTP")
 	}
 
-	if _, err := s.repo.ActivateUserByEmail(ctx, user); err != nil {
+	verified, err := events.New(ctx, events.UserEmailVerified, user.ID.String(), events.UserEmailVerifiedData{
+		UserID:     user.ID.String(),
+		Email:      user.Email,
+		VerifiedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return false, customError.NewInternalError(err)
+	}
+	if _, err := s.repo.ActivateUserByEmail(ctx, user, verified); err != nil {
 		return false, customError.NewInternalError(err)
 	}
+	s.identities.Invalidate(ctx, auth.UserTypeUser, user.ID.String())
 	return true, nil
 }
+
+// alertPasswordChanged sends a security notice in the background; it ignores opt-outs and quiet hours
+func (s *UserService) alertPasswordChanged(ctx context.Context, user *models.User) {
+	go s.dispatcher.Dispatch(context.WithoutCancel(ctx), notificationService.Notification{
+		OwnerID:   user.ID.String(),
+		OwnerType: auth.UserTypeUser,
+		Category:  notificationModels.CategorySecurity,
+		Channels:  []notificationModels.Channel{notificationModels.ChannelPush, notificationModels.ChannelInApp},
+		Title:     "Password changed",
+		Body:      "Your password was just changed. If this wasn't you, reset it immediately.",
+	})
+}
+
+func passwordChangedEvent(ctx context.Context, user *models.User, reason string) (outbox.Message, error) {
+	return events.New(ctx, events.UserPasswordChanged, user.ID.String(), events.UserPasswordChangedData{
+		UserID:    user.ID.String(),
+		Reason:    reason,
+		ChangedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,52 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Keys look like rsk_<8 hex chars>_<secret>. The "rsk_<8 hex chars>" part is stored
+// in clear as a lookup prefix, the full key is only ever stored hashed.
+const keyPrefix = "rsk"
+
+// Generate creates a new random API key returning the raw key, its public prefix and its hash
+func Generate() (string, string, string, error) {
+	var id [4]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate key id: %w", err)
+	}
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate key secret: %w", err)
+	}
+
+	prefix := keyPrefix + "_" + hex.EncodeToString(id[:])
+	raw := prefix + "_" + base64.RawURLEncoding.EncodeToString(secret[:])
+	return raw, prefix, Hash(raw), nil
+}
+
+// Prefix extracts the public lookup prefix from a raw key
+func Prefix(raw string) (string, bool) {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[0] + "_" + parts[1], true
+}
+
+// Hash returns the hex encoded SHA-256 of the raw key. Keys carry 256 bits of
+// entropy so a fast hash is sufficient and keeps per-request checks cheap.
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches compares a raw key against a stored hash in constant time
+func Matches(raw string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(raw)), []byte(hash)) == 1
+}
@@ -8,18 +8,34 @@ import (
 )
 
 type TokenClaims struct {
-	UserID            string   `json:"sub"`
-	TokenType         string   `json:"typ"`
-	UserType          UserType `json:"user"`
-	PasswordChangedAt int64    `json:"lpc"`
+	UserID            string       `json:"sub"`
+	TokenType         string       `json:"typ"`
+	UserType          UserType     `json:"user"`
+	PasswordChangedAt int64        `json:"lpc"`
+	Actor             *ActorClaims `json:"act,omitempty"`
+	// PasswordExpired marks a token that may only be used to change an expired password
+	PasswordExpired bool `json:"pwx,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ActorClaims identifies who is acting on behalf of the subject (RFC 8693 "act" claim)
+type ActorClaims struct {
+	UserID   string   `json:"sub"`
+	UserType UserType `json:"user"`
+}
+
+// IsImpersonated reports whether the token was minted for an admin acting as the subject
+func (c *TokenClaims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
 type TokenService struct {
 	accessSecret  string
 	refreshSecret string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	// impersonationExpiry defaults to ImpersonationTokenExpiry
+	impersonationExpiry time.Duration
 }
 
 const (
@@ -27,12 +43,29 @@ const (
 	TokenTypeRefresh = "refresh"
 )
 
+// PasswordChangeTokenExpiry is the lifetime of the restricted token issued when a login
+// finds the password past its maximum age
+const PasswordChangeTokenExpiry = 15 * time.Minute
+
+// ImpersonationTokenExpiry is the default lifetime of impersonation tokens. It is kept
+// short because they cannot be refreshed.
+const ImpersonationTokenExpiry = 15 * time.Minute
+
 func NewTokenService(accessSecret, refreshSecret string, accessExpiry, refreshExpiry time.Duration) *TokenService {
 	return &TokenService{
 		accessSecret:  accessSecret,
 		refreshSecret: refreshSecret,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
+
+		impersonationExpiry: ImpersonationTokenExpiry,
+	}
+}
+
+// SetImpersonationExpiry changes the lifetime of impersonation tokens issued from now on
+func (s *TokenService) SetImpersonationExpiry(expiry time.Duration) {
+	if expiry > 0 {
+		s.impersonationExpiry = expiry
 	}
 }
 
@@ -50,6 +83,55 @@ func (s *TokenService) GenerateRefreshToken(userID string, userType UserType, pa
 	return s.generateToken(userID, s.refreshSecret, s.refreshExpiry, TokenTypeRefresh, userType, passwordChangedAt)
 }
 
+// GenerateImpersonationToken mints a short-lived access token for userID carrying the actor in the "act" claim
+func (s *TokenService) GenerateImpersonationToken(userID string, userType UserType, passwordChangedAt *time.Time, actorID string, actorType UserType) (string, time.Time, error) {
+	if !userType.IsValid() {
+		return "", time.Time{}, fmt.Errorf("invalid user type: %s", userType)
+	}
+
+	expiresAt := time.Now().Add(s.impersonationExpiry)
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"exp":  expiresAt.Unix(),
+		"iat":  time.Now().Unix(),
+		"typ":  TokenTypeAccess,
+		"user": string(userType),
+		"lpc":  passwordChangedAt.UTC().UnixNano(),
+		"act": map[string]string{
+			"sub":  actorID,
+			"user": string(actorType),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(s.accessSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// GeneratePasswordChangeToken mints a short-lived access token that is only accepted by the
+// change password endpoint. It cannot be refreshed.
+func (s *TokenService) GeneratePasswordChangeToken(userID string, userType UserType, passwordChangedAt *time.Time) (string, error) {
+	if !userType.IsValid() {
+		return "", fmt.Errorf("invalid user type: %s", userType)
+	}
+
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"exp":  time.Now().Add(PasswordChangeTokenExpiry).Unix(),
+		"iat":  time.Now().Unix(),
+		"typ":  TokenTypeAccess,
+		"user": string(userType),
+		"lpc":  passwordChangedAt.UTC().UnixNano(),
+		"pwx":  true,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.accessSecret))
+}
+
 func (s *TokenService) generateToken(userID, secret string, expiry time.Duration, tokenType string, userType UserType, passwordChangedAt *time.Time) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":  userID,
@@ -1,18 +1,25 @@
 package auth
 
-import "context"
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"ride-sharing/internal/pkg/errors"
+)
 
 type UserType string
 
 const (
-	UserTypeAdmin UserType = "admin"
-	UserTypeUser  UserType = "user"
-	UserTypeRider UserType = "rider"
+	UserTypeAdmin   UserType = "admin"
+	UserTypeUser    UserType = "user"
+	UserTypeRider   UserType = "rider"
+	UserTypeService UserType = "service"
 )
 
 func (u UserType) IsValid() bool {
 	switch u {
-	case UserTypeAdmin, UserTypeUser, UserTypeRider:
+	case UserTypeAdmin, UserTypeUser, UserTypeRider, UserTypeService:
 		return true
 	}
 	return false
@@ -21,3 +28,92 @@ func (u UserType) IsValid() bool {
 type UserProvider interface {
 	GetByID(ctx context.Context, id string, userType UserType) (interface{}, error)
 }
+
+// Identity is implemented by every account type that can hold a JWT
+type Identity interface {
+	GetPasswordChangedAt() *time.Time
+	// GetSuspendedAt is non-nil while the account is suspended
+	GetSuspendedAt() *time.Time
+}
+
+// IdentitySnapshot is the compact form of an account kept in the identity cache. It holds
+// only what token validation needs.
+type IdentitySnapshot struct {
+	ID                string     `json:"id"`
+	UserType          UserType   `json:"user_type"`
+	PasswordChangedAt *time.Time `json:"password_changed_at"`
+	SuspendedAt       *time.Time `json:"suspended_at,omitempty"`
+}
+
+func (s *IdentitySnapshot) GetPasswordChangedAt() *time.Time {
+	return s.PasswordChangedAt
+}
+
+func (s *IdentitySnapshot) GetSuspendedAt() *time.Time {
+	return s.SuspendedAt
+}
+
+// CheckActor verifies that the admin behind an impersonation token may still act. A
+// non-empty reason means the token must be refused; lookup failures are returned as errors.
+func CheckActor(ctx context.Context, providers map[UserType]UserProvider, actor *ActorClaims) (string, error) {
+	provider, exists := providers[actor.UserType]
+	if !exists || actor.UserType != UserTypeAdmin {
+		return "invalid impersonation actor", nil
+	}
+
+	account, err := provider.GetByID(ctx, actor.UserID, actor.UserType)
+	if err != nil {
+		var appErr *errors.AppError
+		if stdErrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			return "impersonation actor not found", nil
+		}
+		return "", err
+	}
+
+	identity, ok := account.(Identity)
+	if !ok {
+		return "invalid impersonation actor", nil
+	}
+	if identity.GetSuspendedAt() != nil {
+		return "impersonation actor suspended", nil
+	}
+	return "", nil
+}
+
+// IdentityInvalidator drops cached identities after the account behind them changes
+type IdentityInvalidator interface {
+	Invalidate(ctx context.Context, userType UserType, id string)
+}
+
+// Scopes granted to API keys
+const (
+	ScopeRidesRead  = "rides:read"
+	ScopeRidesWrite = "rides:write"
+	ScopeUsersRead  = "users:read"
+	// ScopeAccountRead lets a partner look up its own service account
+	ScopeAccountRead = "account:read"
+	// ScopeAuthIntrospect lets internal services call the gRPC AuthService
+	ScopeAuthIntrospect = "auth:introspect"
+)
+
+// APIKeyIdentity describes the service account behind an authenticated API key
+type APIKeyIdentity struct {
+	ServiceAccountID string
+	KeyID            string
+	Scopes           []string
+	Account          interface{}
+}
+
+func (i *APIKeyIdentity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthenticator resolves a raw X-API-Key header value to its service account
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*APIKeyIdentity, error)
+}
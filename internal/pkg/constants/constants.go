@@ -6,4 +6,12 @@ const (
 	OTPUserRegister   OTPType = "USER_REGISTER"
 	OTPForgetPassword OTPType = "FORGET_PASSWORD"
 	OTPVerifyEmail    OTPType = "VERIFY_EMAIL"
+	OTPPasswordless   OTPType = "PASSWORDLESS_LOGIN"
+)
+
+type LoginCodeChannel string
+
+const (
+	LoginCodeChannelEmail LoginCodeChannel = "EMAIL"
+	LoginCodeChannelSMS   LoginCodeChannel = "SMS"
 )
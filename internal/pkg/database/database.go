@@ -16,6 +16,11 @@ type DBConfig struct {
 	User     string
 	Password string
 	Name     string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnectTimeout  time.Duration
 }
 
 func NewPostgresDB(cfg DBConfig) (*gorm.DB, error) {
@@ -31,18 +36,25 @@ func NewPostgresDB(cfg DBConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(metricsPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register database tracing: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying DB: %w", err)
 	}
 
 	// Set connection pool parameters
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	// Test the connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
 	if err := sqlDB.PingContext(ctx); err != nil {
@@ -52,10 +64,11 @@ func NewPostgresDB(cfg DBConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
-func AutoMigrate(db *gorm.DB, models ...interface{}) error {
-	db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`)
-	if err := db.AutoMigrate(models...); err != nil {
-		return fmt.Errorf("failed to auto-migrate models: %w", err)
+// Ping checks that Postgres answers on one of the pooled connections
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
-	return nil
+	return sqlDB.PingContext(ctx)
 }
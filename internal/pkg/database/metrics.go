@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"ride-sharing/internal/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// metricsPlugin times every GORM statement into the db_query_duration_seconds histogram
+type metricsPlugin struct{}
+
+func (metricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (metricsPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, p := range processors {
+		if err := p.before("metrics:before_"+p.operation, startTimer); err != nil {
+			return err
+		}
+		if err := p.after("metrics:after_"+p.operation, observe(p.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		result := "success"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			result = "failure"
+		}
+		metrics.DBQueryDuration.WithLabelValues(operation, table, result).Observe(time.Since(start).Seconds())
+	}
+}
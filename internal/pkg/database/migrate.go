@@ -0,0 +1,415 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"ride-sharing/internal/pkg/logging"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the Postgres advisory lock held while migrating, so only one
+// instance applies migrations when several replicas start together
+const migrationLockKey = 7_320_041
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// Migration is a numbered pair of SQL scripts embedded in the binary
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 of the up script, checked against what was applied
+}
+
+// MigrationStatus describes a migration known to the binary, the database or both
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the up script changed after it was applied
+	Modified bool
+	// Unknown is set when the database has a version this binary does not ship
+	Unknown bool
+}
+
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations. Each migration runs in its own transaction
+// and is recorded in schema_migrations with the checksum of its up script.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations returns the embedded migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest returns the highest embedded version, or 0 when there are none
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration and returns the versions applied. It never rolls
+// back: versions applied by a newer binary are left in place, so an older release can
+// still start during a rollback or a rolling deploy.
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	var done []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, version := range appliedVersions(applied) {
+			if m.find(version) == nil {
+				logging.GetLogger().WithContext(ctx).Warn("database has a migration this binary does not ship",
+					zap.Int("version", version), zap.String("name", applied[version].Name))
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the given number of most recently applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	if steps <= 0 {
+		return nil, nil
+	}
+
+	var done []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && len(done) < steps; i-- {
+			if err := m.rollback(ctx, conn, versions[i]); err != nil {
+				return err
+			}
+			done = append(done, versions[i])
+		}
+		return nil
+	})
+	return done, err
+}
+
+// To migrates up or down until version is the latest applied migration. Version 0 rolls
+// back everything.
+func (m *Migrator) To(ctx context.Context, version int) ([]int, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	var done []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		// Roll back anything above the target, newest first
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && versions[i] > version; i-- {
+			if err := m.rollback(ctx, conn, versions[i]); err != nil {
+				return err
+			}
+			done = append(done, versions[i])
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every migration with whether and when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock.
+// The lock is session scoped, so it has to be taken and released on the same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	applied := make(map[int]appliedMigration)
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[record.Version] = record
+	}
+	return applied, rows.Err()
+}
+
+// verify refuses to migrate when an applied script was edited afterwards, since the
+// database would no longer match what the binary expects
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	var errs []error
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if ok && record.Checksum != migration.Checksum {
+			errs = append(errs, fmt.Errorf("migration %d_%s was modified after it was applied", migration.Version, migration.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return runInTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, migration.Checksum,
+		)
+		return err
+	})
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, version int) error {
+	migration := m.find(version)
+	if migration == nil {
+		return fmt.Errorf("cannot roll back migration %d: not shipped with this binary", version)
+	}
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s is irreversible", migration.Version, migration.Name)
+	}
+
+	return runInTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func runInTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func appliedVersions(applied map[int]appliedMigration) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// loadMigrations pairs up the NNNN_name.up.sql and NNNN_name.down.sql files. A missing
+// down script marks the migration as irreversible.
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
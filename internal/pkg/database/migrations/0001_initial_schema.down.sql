@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS inbox_items;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS service_accounts;
+DROP TABLE IF EXISTS admins;
+DROP TABLE IF EXISTS riders;
+DROP TABLE IF EXISTS password_histories;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,243 @@
+-- Baseline matching the schema previously created by GORM AutoMigrate. Every statement is
+-- guarded so databases created by AutoMigrate can adopt migrations without changes. A table
+-- that already exists is skipped by CREATE TABLE IF NOT EXISTS, so columns added after it
+-- was first auto-migrated are added separately below it.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    full_name text NOT NULL,
+    phone text NOT NULL UNIQUE,
+    address text NOT NULL,
+    email text NOT NULL UNIQUE,
+    password text NOT NULL,
+    active boolean DEFAULT false,
+    locale text NOT NULL DEFAULT 'en',
+    password_changed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+-- The first release only auto-migrated users, without locale
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale text NOT NULL DEFAULT 'en';
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    password text NOT NULL,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_password_histories_user_id ON password_histories (user_id);
+CREATE INDEX IF NOT EXISTS idx_password_histories_created_at ON password_histories (created_at);
+
+CREATE TABLE IF NOT EXISTS riders (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    full_name text NOT NULL,
+    phone text NOT NULL UNIQUE,
+    email text NOT NULL UNIQUE,
+    password text NOT NULL,
+    license_number text NOT NULL UNIQUE,
+    license_issue_date timestamptz NOT NULL,
+    license_expiry_date timestamptz NOT NULL,
+    license_category text NOT NULL CHECK (license_category IN ('A', 'B', 'K')),
+    blue_book_number text NOT NULL UNIQUE,
+    vehicle_type text NOT NULL,
+    vehicle_model text NOT NULL CHECK (vehicle_model IN ('A', 'B', 'K')),
+    vehicle_year bigint NOT NULL,
+    is_approved boolean DEFAULT false,
+    approval_status text DEFAULT 'pending' CHECK (approval_status IN ('pending', 'approved', 'rejected')),
+    rating numeric DEFAULT 0.0,
+    total_trips bigint DEFAULT 0,
+    online_status boolean DEFAULT false,
+    password_changed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_riders_deleted_at ON riders (deleted_at);
+
+CREATE TABLE IF NOT EXISTS admins (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    full_name text NOT NULL,
+    email text NOT NULL UNIQUE,
+    password text NOT NULL,
+    active boolean DEFAULT true,
+    password_changed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_admins_deleted_at ON admins (deleted_at);
+
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    name text NOT NULL UNIQUE,
+    description text,
+    active boolean DEFAULT true
+);
+CREATE INDEX IF NOT EXISTS idx_service_accounts_deleted_at ON service_accounts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    service_account_id uuid NOT NULL,
+    name text NOT NULL,
+    prefix text NOT NULL,
+    key_hash text NOT NULL,
+    scopes jsonb NOT NULL,
+    rate_limit_per_minute bigint NOT NULL DEFAULT 60,
+    last_used_at timestamptz,
+    expires_at timestamptz,
+    revoked_at timestamptz,
+    rotated_from_id uuid
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account_id ON api_keys (service_account_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action text NOT NULL,
+    actor_id text NOT NULL,
+    actor_type text NOT NULL,
+    subject_id text,
+    subject_type text,
+    method text,
+    path text,
+    status bigint,
+    ip text,
+    request_id text,
+    metadata jsonb,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_subject_id ON audit_logs (subject_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial PRIMARY KEY,
+    event_id uuid NOT NULL,
+    topic text NOT NULL,
+    aggregate_key text NOT NULL,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    headers jsonb,
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL,
+    published_at timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_event_id ON outbox_events (event_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_key ON outbox_events (aggregate_key);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_next_attempt_at ON outbox_events (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at ON outbox_events (published_at);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id uuid PRIMARY KEY,
+    kind text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL,
+    sent_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_status ON notification_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_next_attempt_at ON notification_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS devices (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    owner_id uuid NOT NULL,
+    owner_type text NOT NULL,
+    platform text NOT NULL,
+    token text NOT NULL,
+    last_seen_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_devices_deleted_at ON devices (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_devices_owner ON devices (owner_id, owner_type);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_token ON devices (token);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    owner_id uuid NOT NULL,
+    owner_type text NOT NULL,
+    channels jsonb NOT NULL,
+    quiet_hours_start text,
+    quiet_hours_end text,
+    timezone text NOT NULL DEFAULT 'UTC'
+);
+CREATE INDEX IF NOT EXISTS idx_notification_preferences_deleted_at ON notification_preferences (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_owner ON notification_preferences (owner_id, owner_type);
+
+CREATE TABLE IF NOT EXISTS inbox_items (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    owner_id uuid NOT NULL,
+    owner_type text NOT NULL,
+    category text NOT NULL,
+    title text NOT NULL,
+    body text NOT NULL,
+    data jsonb,
+    read_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_inbox_items_deleted_at ON inbox_items (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_inbox_items_owner ON inbox_items (owner_id, owner_type);
+CREATE INDEX IF NOT EXISTS idx_inbox_items_read_at ON inbox_items (read_at);
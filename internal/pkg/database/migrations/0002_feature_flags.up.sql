@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    is_deleted boolean DEFAULT false,
+    last_login_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    deleted_by bigint,
+    key text NOT NULL UNIQUE,
+    description text,
+    enabled boolean NOT NULL DEFAULT false,
+    percentage bigint NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    user_types jsonb NOT NULL DEFAULT '[]',
+    cities jsonb NOT NULL DEFAULT '[]',
+    allowlist jsonb NOT NULL DEFAULT '[]'
+);
+CREATE INDEX IF NOT EXISTS idx_feature_flags_deleted_at ON feature_flags (deleted_at);
+
+-- Passwordless login already shipped to everyone; the flag keeps it on until an admin changes it
+INSERT INTO feature_flags (created_at, updated_at, key, description, enabled, percentage)
+VALUES (now(), now(), 'passwordless_login', 'Login with one-time codes sent to the user', true, 100)
+ON CONFLICT (key) DO NOTHING;
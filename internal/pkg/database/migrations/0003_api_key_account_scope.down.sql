@@ -0,0 +1,2 @@
+UPDATE api_keys
+SET scopes = scopes - 'account:read';
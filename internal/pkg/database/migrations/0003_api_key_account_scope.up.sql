@@ -0,0 +1,4 @@
+-- /partner/me now requires the account:read scope; keys issued before it keep working
+UPDATE api_keys
+SET scopes = scopes || '["account:read"]'::jsonb
+WHERE NOT scopes ? 'account:read';
@@ -0,0 +1,3 @@
+ALTER TABLE admins DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE riders DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
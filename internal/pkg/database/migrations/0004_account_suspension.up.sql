@@ -0,0 +1,4 @@
+-- A suspended account cannot log in and its tokens are refused until the suspension is lifted
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at timestamptz;
+ALTER TABLE riders ADD COLUMN IF NOT EXISTS suspended_at timestamptz;
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS suspended_at timestamptz;
@@ -8,13 +8,15 @@ import (
 type ErrorType string
 
 const (
-	ErrorTypeValidation   ErrorType = "VALIDATION_ERROR"
-	ErrorTypeVerification ErrorType = "VERIFICATION_ERROR"
-	ErrorTypeConflict     ErrorType = "CONFLICT_ERROR"
-	ErrorTypeNotFound     ErrorType = "NOT_FOUND_ERROR"
-	ErrorTypeUnauthorized ErrorType = "UNAUTHORIZED_ERROR"
-	ErrorTypeForbidden    ErrorType = "FORBIDDEN_ERROR"
-	ErrorTypeInternal     ErrorType = "INTERNAL_ERROR"
+	ErrorTypeValidation    ErrorType = "VALIDATION_ERROR"
+	ErrorTypeVerification  ErrorType = "VERIFICATION_ERROR"
+	ErrorTypeConflict      ErrorType = "CONFLICT_ERROR"
+	ErrorTypeNotFound      ErrorType = "NOT_FOUND_ERROR"
+	ErrorTypeUnauthorized  ErrorType = "UNAUTHORIZED_ERROR"
+	ErrorTypeForbidden     ErrorType = "FORBIDDEN_ERROR"
+	ErrorTypeTooMany       ErrorType = "TOO_MANY_REQUESTS_ERROR"
+	ErrorTypeUnprocessable ErrorType = "UNPROCESSABLE_ERROR"
+	ErrorTypeInternal      ErrorType = "INTERNAL_ERROR"
 )
 
 type AppError struct {
@@ -78,6 +80,20 @@ func NewForbiddenError(message string) *AppError {
 	}
 }
 
+func NewTooManyRequestsError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeTooMany,
+		Message: message,
+	}
+}
+
+func NewUnprocessableError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeUnprocessable,
+		Message: message,
+	}
+}
+
 func NewInternalError(err error) *AppError {
 	return &AppError{
 		Type:    ErrorTypeInternal,
@@ -100,6 +116,10 @@ func HTTPStatusFromErrorType(t ErrorType) int {
 		return http.StatusForbidden
 	case ErrorTypeVerification:
 		return http.StatusBadRequest
+	case ErrorTypeTooMany:
+		return http.StatusTooManyRequests
+	case ErrorTypeUnprocessable:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,127 @@
+package events
+
+import "time"
+
+// Topics grouped by aggregate; the aggregate ID is used as the message key
+const (
+	TopicUserEvents = "user-events"
+
+	// Topics owned by other services that this service consumes
+	TopicTripEvents         = "trip-events"
+	TopicPaymentEvents      = "payment-events"
+	TopicNotificationEvents = "notification-events"
+)
+
+// Event identifies a catalogued event type and the schema version currently produced.
+// Breaking payload changes must bump Version; additive changes keep it.
+type Event struct {
+	Type    string
+	Version int
+	Topic   string
+}
+
+var (
+	UserRegistered      = Event{Type: "user.registered", Version: 1, Topic: TopicUserEvents}
+	UserEmailVerified   = Event{Type: "user.email_verified", Version: 1, Topic: TopicUserEvents}
+	UserPasswordChanged = Event{Type: "user.password_changed", Version: 1, Topic: TopicUserEvents}
+
+	// NotificationOTPRequested is the fallback used when the notification gRPC service is
+	// unreachable. It has no fixed topic and goes to the configured KAFKA_TOPIC.
+	NotificationOTPRequested = Event{Type: "notification.otp_requested", Version: 1}
+)
+
+// Events produced by other services and consumed here
+var (
+	TripRequested       = Event{Type: "trip.requested", Version: 1, Topic: TopicTripEvents}
+	TripCompleted       = Event{Type: "trip.completed", Version: 1, Topic: TopicTripEvents}
+	PaymentSettled      = Event{Type: "payment.settled", Version: 1, Topic: TopicPaymentEvents}
+	NotificationBounced = Event{Type: "notification.bounced", Version: 1, Topic: TopicNotificationEvents}
+)
+
+// Published lists every event this service may publish
+var Published = []Event{
+	UserRegistered,
+	UserEmailVerified,
+	UserPasswordChanged,
+	NotificationOTPRequested,
+}
+
+// Consumed lists every event owned by another service that this service handles
+var Consumed = []Event{
+	TripRequested,
+	TripCompleted,
+	PaymentSettled,
+	NotificationBounced,
+}
+
+// UserRegisteredData is published when a user account is created, before email verification
+type UserRegisteredData struct {
+	UserID   string `json:"user_id" validate:"required,uuid"`
+	Email    string `json:"email" validate:"required,email"`
+	Phone    string `json:"phone" validate:"required"`
+	FullName string `json:"full_name" validate:"required"`
+	UserType string `json:"user_type" validate:"required"`
+}
+
+// UserEmailVerifiedData is published once the registration OTP has been confirmed
+type UserEmailVerifiedData struct {
+	UserID     string    `json:"user_id" validate:"required,uuid"`
+	Email      string    `json:"email" validate:"required,email"`
+	VerifiedAt time.Time `json:"verified_at" validate:"required"`
+}
+
+// UserPasswordChangedData is published on password change and reset; existing sessions
+// issued before ChangedAt are no longer valid.
+type UserPasswordChangedData struct {
+	UserID    string    `json:"user_id" validate:"required,uuid"`
+	Reason    string    `json:"reason" validate:"required,oneof=change reset"`
+	ChangedAt time.Time `json:"changed_at" validate:"required"`
+}
+
+// TripRequestedData is consumed when the trip service records a trip request
+type TripRequestedData struct {
+	TripID      string    `json:"trip_id" validate:"required,uuid"`
+	UserID      string    `json:"user_id" validate:"required,uuid"`
+	PickupLat   float64   `json:"pickup_lat" validate:"latitude"`
+	PickupLng   float64   `json:"pickup_lng" validate:"longitude"`
+	DropoffLat  float64   `json:"dropoff_lat" validate:"latitude"`
+	DropoffLng  float64   `json:"dropoff_lng" validate:"longitude"`
+	RequestedAt time.Time `json:"requested_at" validate:"required"`
+}
+
+// TripCompletedData is consumed when the trip service records the end of a trip
+type TripCompletedData struct {
+	TripID      string    `json:"trip_id" validate:"required,uuid"`
+	UserID      string    `json:"user_id" validate:"required,uuid"`
+	RiderID     string    `json:"rider_id" validate:"required,uuid"`
+	DistanceKm  float64   `json:"distance_km" validate:"gte=0"`
+	FareAmount  int64     `json:"fare_amount" validate:"gte=0"` // minor units
+	Currency    string    `json:"currency" validate:"required,len=3"`
+	CompletedAt time.Time `json:"completed_at" validate:"required"`
+}
+
+// NotificationOTPRequestedData asks the notification service to deliver a one time code
+type NotificationOTPRequestedData struct {
+	Purpose string `json:"purpose" validate:"required,oneof=USER_REGISTER FORGET_PASSWORD PASSWORDLESS_LOGIN"`
+	Channel string `json:"channel" validate:"required,oneof=EMAIL SMS"`
+	To      string `json:"to" validate:"required"`
+	OTP     string `json:"otp" validate:"required"`
+	Locale  string `json:"locale"`
+}
+
+// PaymentSettledData is consumed when the payment service captures a trip fare
+type PaymentSettledData struct {
+	PaymentID string    `json:"payment_id" validate:"required"`
+	TripID    string    `json:"trip_id" validate:"required"`
+	Amount    int64     `json:"amount" validate:"gte=0"` // minor units
+	Currency  string    `json:"currency" validate:"required,len=3"`
+	SettledAt time.Time `json:"settled_at" validate:"required"`
+}
+
+// NotificationBouncedData is consumed when the notification service gives up on a delivery
+type NotificationBouncedData struct {
+	Channel   string    `json:"channel" validate:"required,oneof=EMAIL SMS PUSH"`
+	Recipient string    `json:"recipient" validate:"required"`
+	Reason    string    `json:"reason"`
+	BouncedAt time.Time `json:"bounced_at" validate:"required"`
+}
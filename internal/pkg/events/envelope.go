@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ride-sharing/internal/pkg/kafka"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/outbox"
+
+	"github.com/google/uuid"
+)
+
+const source = "ride-sharing"
+
+// Envelope wraps every domain event published to Kafka. Consumers should dedupe on ID,
+// since delivery is at-least-once, and switch on Type and Version before decoding Data.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	Source        string          `json:"source"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Kafka headers set on every event so consumers can route without decoding the body
+const (
+	HeaderEventID       = "event-id"
+	HeaderEventType     = kafka.HeaderEventType
+	HeaderSchemaVersion = "schema-version"
+	HeaderCorrelationID = kafka.HeaderCorrelationID
+)
+
+// NewMessage validates data against the registered schema of event and wraps it in an
+// envelope. The correlation ID is taken from the request context when present so events
+// can be traced back to the originating call.
+func NewMessage(ctx context.Context, event Event, key string, data interface{}) (kafka.Message, error) {
+	if err := Validate(event, data); err != nil {
+		return kafka.Message{}, err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to marshal %s payload: %w", event.Type, err)
+	}
+
+	envelope := Envelope{
+		ID:            uuid.New().String(),
+		Type:          event.Type,
+		Version:       event.Version,
+		Source:        source,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID(ctx),
+		Data:          payload,
+	}
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to marshal %s envelope: %w", event.Type, err)
+	}
+
+	headers := map[string]string{
+		HeaderEventID:       envelope.ID,
+		HeaderEventType:     event.Type,
+		HeaderSchemaVersion: strconv.Itoa(event.Version),
+	}
+	if envelope.CorrelationID != "" {
+		headers[HeaderCorrelationID] = envelope.CorrelationID
+	}
+
+	return kafka.Message{
+		Topic:   event.Topic,
+		Key:     key,
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+// New builds the outbox message for a catalogued event, to be enqueued in the same
+// transaction as the change it describes.
+func New(ctx context.Context, event Event, key string, data interface{}) (outbox.Message, error) {
+	msg, err := NewMessage(ctx, event, key, data)
+	if err != nil {
+		return outbox.Message{}, err
+	}
+
+	return outbox.Message{
+		EventID:   uuid.MustParse(msg.Headers[HeaderEventID]),
+		Topic:     msg.Topic,
+		Key:       msg.Key,
+		EventType: event.Type,
+		Payload:   json.RawMessage(msg.Value),
+		Headers:   msg.Headers,
+	}, nil
+}
+
+func correlationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(logging.CorrelationID).(string); ok && id != "" {
+		return id
+	}
+	id, _ := ctx.Value(logging.RequestIDKey).(string)
+	return id
+}
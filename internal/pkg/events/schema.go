@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"ride-sharing/internal/pkg/kafka"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Field is the contract for one payload property, in JSON Schema terms
+type Field struct {
+	Type     string `json:"type"` // string, number, integer, boolean, object or array
+	Required bool   `json:"required,omitempty"`
+}
+
+// Schema is the published contract of one event version. Payload is the Go type both the
+// producer and consumer use. The field contract of every version is frozen in
+// testdata/schemas.json, and the package tests fail when a payload type drifts from it.
+type Schema struct {
+	Event   Event
+	Payload interface{}
+}
+
+var payloadValidator = validator.New()
+
+// registry holds every schema version keyed by event type, then version
+var registry = map[string]map[int]Schema{}
+
+func register(schema Schema) {
+	versions, ok := registry[schema.Event.Type]
+	if !ok {
+		versions = map[int]Schema{}
+		registry[schema.Event.Type] = versions
+	}
+	versions[schema.Event.Version] = schema
+}
+
+func init() {
+	register(Schema{Event: UserRegistered, Payload: UserRegisteredData{}})
+	register(Schema{Event: UserEmailVerified, Payload: UserEmailVerifiedData{}})
+	register(Schema{Event: UserPasswordChanged, Payload: UserPasswordChangedData{}})
+	register(Schema{Event: TripRequested, Payload: TripRequestedData{}})
+	register(Schema{Event: TripCompleted, Payload: TripCompletedData{}})
+	register(Schema{Event: NotificationOTPRequested, Payload: NotificationOTPRequestedData{}})
+	register(Schema{Event: PaymentSettled, Payload: PaymentSettledData{}})
+	register(Schema{Event: NotificationBounced, Payload: NotificationBouncedData{}})
+}
+
+// Lookup returns the schema registered for an event type and version
+func Lookup(eventType string, version int) (Schema, bool) {
+	schema, ok := registry[eventType][version]
+	return schema, ok
+}
+
+// Validate checks that data is the registered payload type of event and satisfies its rules
+func Validate(event Event, data interface{}) error {
+	schema, ok := Lookup(event.Type, event.Version)
+	if !ok {
+		return fmt.Errorf("no schema registered for %s v%d", event.Type, event.Version)
+	}
+	if reflect.TypeOf(data) != reflect.TypeOf(schema.Payload) {
+		return fmt.Errorf("%s v%d expects %T, got %T", event.Type, event.Version, schema.Payload, data)
+	}
+	if err := payloadValidator.Struct(data); err != nil {
+		return fmt.Errorf("invalid %s v%d payload: %w", event.Type, event.Version, err)
+	}
+	return nil
+}
+
+// Fields describes the JSON contract of the payload, derived from its json and validate tags
+func (s Schema) Fields() map[string]Field {
+	return describe(reflect.TypeOf(s.Payload))
+}
+
+// describe derives the JSON field contract from a payload struct's json and validate tags
+func describe(t reflect.Type) map[string]Field {
+	fields := map[string]Field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = Field{
+			Type:     jsonType(sf.Type),
+			Required: strings.Contains(sf.Tag.Get("validate"), "required"),
+		}
+	}
+	return fields
+}
+
+func jsonType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	default:
+		return "object"
+	}
+}
+
+// Handle registers a consumer handler for event that decodes and validates the payload
+// against the schema version named in the message headers before calling handler.
+func Handle[T any](consumer *kafka.Consumer, event Event, handler func(ctx context.Context, envelope Envelope, data T) error) {
+	consumer.Handle(event.Type, decoding(event, handler))
+}
+
+// decoding wraps handler in the kafka.Handler that Handle registers. Messages that do not
+// decode or validate are marked permanent, retrying them cannot help.
+func decoding[T any](event Event, handler func(ctx context.Context, envelope Envelope, data T) error) kafka.Handler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		version, err := strconv.Atoi(msg.Headers[HeaderSchemaVersion])
+		if err != nil {
+			return kafka.Permanent(fmt.Errorf("missing or invalid %s header on %s", HeaderSchemaVersion, event.Type))
+		}
+		schema, ok := Lookup(event.Type, version)
+		if !ok {
+			return kafka.Permanent(fmt.Errorf("unsupported %s schema version %d", event.Type, version))
+		}
+		if _, ok := schema.Payload.(T); !ok {
+			return kafka.Permanent(fmt.Errorf("%s v%d is decoded as %T, handler expects %T", event.Type, version, schema.Payload, *new(T)))
+		}
+
+		var envelope Envelope
+		if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+			return kafka.Permanent(fmt.Errorf("failed to decode %s envelope: %w", event.Type, err))
+		}
+
+		var data T
+		if err := json.Unmarshal(envelope.Data, &data); err != nil {
+			return kafka.Permanent(fmt.Errorf("failed to decode %s payload: %w", event.Type, err))
+		}
+		if err := payloadValidator.Struct(data); err != nil {
+			return kafka.Permanent(fmt.Errorf("invalid %s v%d payload: %w", event.Type, version, err))
+		}
+		return handler(ctx, envelope, data)
+	}
+}
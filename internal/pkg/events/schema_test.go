@@ -0,0 +1,218 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"ride-sharing/internal/pkg/kafka"
+)
+
+const (
+	sampleID      = "2f1f6c1e-8a4b-4c53-9a3e-0f3c2a9d7b11"
+	otherSampleID = "7d0e5b9c-3c1a-4e2f-8b6d-5a4c3b2a1f00"
+)
+
+var sampleTime = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+// samples holds a valid payload for every registered schema version, keyed like schemaKey
+var samples = map[string]func(t *testing.T){
+	schemaKey(UserRegistered): roundTrip(UserRegistered, UserRegisteredData{
+		UserID: sampleID, Email: "rider@example.com", Phone: "+9779800000000", FullName: "Sam Rider", UserType: "user",
+	}),
+	schemaKey(UserEmailVerified): roundTrip(UserEmailVerified, UserEmailVerifiedData{
+		UserID: sampleID, Email: "rider@example.com", VerifiedAt: sampleTime,
+	}),
+	schemaKey(UserPasswordChanged): roundTrip(UserPasswordChanged, UserPasswordChangedData{
+		UserID: sampleID, Reason: "reset", ChangedAt: sampleTime,
+	}),
+	schemaKey(TripRequested): roundTrip(TripRequested, TripRequestedData{
+		TripID: sampleID, UserID: otherSampleID, PickupLat: 27.7172, PickupLng: 85.324, DropoffLat: 27.6915, DropoffLng: 85.342, RequestedAt: sampleTime,
+	}),
+	schemaKey(TripCompleted): roundTrip(TripCompleted, TripCompletedData{
+		TripID: sampleID, UserID: otherSampleID, RiderID: sampleID, DistanceKm: 4.2, FareAmount: 35000, Currency: "NPR", CompletedAt: sampleTime,
+	}),
+	schemaKey(NotificationOTPRequested): roundTrip(NotificationOTPRequested, NotificationOTPRequestedData{
+		Purpose: "PASSWORDLESS_LOGIN", Channel: "SMS", To: "+9779800000000", OTP: "123456", Locale: "ne",
+	}),
+	schemaKey(PaymentSettled): roundTrip(PaymentSettled, PaymentSettledData{
+		PaymentID: "pay_123", TripID: sampleID, Amount: 35000, Currency: "NPR", SettledAt: sampleTime,
+	}),
+	schemaKey(NotificationBounced): roundTrip(NotificationBounced, NotificationBouncedData{
+		Channel: "EMAIL", Recipient: "rider@example.com", Reason: "mailbox full", BouncedAt: sampleTime,
+	}),
+}
+
+// snapshotFile freezes the field contract of every published schema version. Entries are
+// never rewritten: a breaking change needs a new version, an added optional field is
+// recorded by hand in the same change.
+const snapshotFile = "testdata/schemas.json"
+
+var update = flag.Bool("update", false, "record schema versions missing from "+snapshotFile)
+
+// TestSchemaSnapshot compares every registered payload type with its frozen contract, so a
+// change that would break producers or consumers of an existing version fails CI.
+func TestSchemaSnapshot(t *testing.T) {
+	snapshot := map[string]map[string]Field{}
+	raw, err := os.ReadFile(snapshotFile)
+	if err != nil && !(*update && errors.Is(err, fs.ErrNotExist)) {
+		t.Fatal(err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(raw, &snapshot); err != nil {
+			t.Fatalf("%s: %v", snapshotFile, err)
+		}
+	}
+
+	registered := map[string]bool{}
+	recorded := false
+	for eventType, versions := range registry {
+		for version, schema := range versions {
+			key := schemaKey(Event{Type: eventType, Version: version})
+			registered[key] = true
+
+			frozen, ok := snapshot[key]
+			if !ok {
+				if *update {
+					snapshot[key] = schema.Fields()
+					recorded = true
+					continue
+				}
+				t.Errorf("%s is not in %s, record it with go test -run TestSchemaSnapshot -update", key, snapshotFile)
+				continue
+			}
+			for _, problem := range compareFields(frozen, schema.Fields()) {
+				t.Errorf("%s: %s", key, problem)
+			}
+		}
+	}
+	for key := range snapshot {
+		if !registered[key] {
+			t.Errorf("%s is in %s but no longer registered, older messages could not be decoded", key, snapshotFile)
+		}
+	}
+
+	if recorded {
+		out, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(snapshotFile, append(out, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// compareFields lists the differences between a frozen contract and the current payload type
+func compareFields(frozen, actual map[string]Field) []string {
+	var problems []string
+	for name, field := range frozen {
+		got, ok := actual[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("field %q was removed, publish a new version instead", name))
+		case got.Type != field.Type:
+			problems = append(problems, fmt.Sprintf("field %q is %s, the contract says %s; publish a new version instead", name, got.Type, field.Type))
+		case got.Required != field.Required:
+			problems = append(problems, fmt.Sprintf("field %q required=%t, the contract says %t; publish a new version instead", name, got.Required, field.Required))
+		}
+	}
+	for name, field := range actual {
+		if _, ok := frozen[name]; ok {
+			continue
+		}
+		if field.Required {
+			problems = append(problems, fmt.Sprintf("new field %q is required, publish a new version instead", name))
+		} else {
+			problems = append(problems, fmt.Sprintf("new optional field %q is not in %s, add it there", name, snapshotFile))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// TestRoundTrip publishes every registered payload and decodes it the way consumers do,
+// so producer and consumer types cannot drift apart unnoticed.
+func TestRoundTrip(t *testing.T) {
+	for eventType, versions := range registry {
+		for version := range versions {
+			key := schemaKey(Event{Type: eventType, Version: version})
+			test, ok := samples[key]
+			if !ok {
+				t.Errorf("no sample payload for %s, add one to samples", key)
+				continue
+			}
+			t.Run(key, test)
+		}
+	}
+}
+
+func roundTrip[T any](event Event, sample T) func(t *testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		if _, err := NewMessage(ctx, event, "key", *new(T)); err == nil {
+			t.Errorf("NewMessage accepted an empty %T", sample)
+		}
+
+		msg, err := NewMessage(ctx, event, "key", sample)
+		if err != nil {
+			t.Fatalf("NewMessage: %v", err)
+		}
+
+		var decoded T
+		var envelope Envelope
+		handle := decoding(event, func(_ context.Context, e Envelope, data T) error {
+			envelope, decoded = e, data
+			return nil
+		})
+		if err := handle(ctx, msg); err != nil {
+			t.Fatalf("decoding: %v", err)
+		}
+
+		if envelope.Type != event.Type || envelope.Version != event.Version {
+			t.Errorf("envelope is %s v%d, want %s v%d", envelope.Type, envelope.Version, event.Type, event.Version)
+		}
+		if envelope.ID != msg.Headers[HeaderEventID] {
+			t.Errorf("envelope ID %q does not match the %s header %q", envelope.ID, HeaderEventID, msg.Headers[HeaderEventID])
+		}
+		if !reflect.DeepEqual(decoded, sample) {
+			t.Errorf("decoded %+v, want %+v", decoded, sample)
+		}
+	}
+}
+
+func TestDecodingRejectsUnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	msg, err := NewMessage(ctx, UserRegistered, "key", UserRegisteredData{
+		UserID: sampleID, Email: "rider@example.com", Phone: "+9779800000000", FullName: "Sam Rider", UserType: "user",
+	})
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+	msg.Headers[HeaderSchemaVersion] = "99"
+
+	handle := decoding(UserRegistered, func(context.Context, Envelope, UserRegisteredData) error {
+		t.Error("handler called for an unsupported schema version")
+		return nil
+	})
+	err = handle(ctx, msg)
+	if err == nil {
+		t.Fatal("decoding accepted an unsupported schema version")
+	}
+	if !kafka.IsPermanent(err) {
+		t.Errorf("decoding error %v is retryable, want it dead-lettered straight away", err)
+	}
+}
+
+func schemaKey(event Event) string {
+	return fmt.Sprintf("%s/v%d", event.Type, event.Version)
+}
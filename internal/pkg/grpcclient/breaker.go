@@ -0,0 +1,91 @@
+package grpcclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("notification service circuit is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops calling a failing dependency for a cooldown period once
+// FailureThreshold consecutive calls have failed, then lets a single probe through.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            circuitState
+	failures         int
+	openedAt         time.Time
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &CircuitBreaker{failureThreshold: failureThreshold, cooldown: cooldown}
+}
+
+// Execute runs fn unless the circuit is open, and records its outcome
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	b.record(err == nil)
+	return err
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// A probe is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Open reports whether calls are currently being rejected
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == circuitOpen && time.Since(b.openedAt) < b.cooldown
+}
@@ -0,0 +1,158 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeliveryKind string
+
+const (
+	DeliveryRegisterEmail       DeliveryKind = "REGISTER_EMAIL"
+	DeliveryForgetPasswordEmail DeliveryKind = "FORGET_PASSWORD_EMAIL"
+	DeliveryLoginCode           DeliveryKind = "LOGIN_CODE"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending  DeliveryStatus = "PENDING"
+	DeliverySent     DeliveryStatus = "SENT"
+	DeliveryFallback DeliveryStatus = "FALLBACK" // handed to Kafka after the gRPC attempts ran out
+	DeliveryFailed   DeliveryStatus = "FAILED"
+)
+
+// DeliveryPayload carries the arguments of the RPC a delivery will make
+type DeliveryPayload struct {
+	To      string `json:"to,omitempty"`
+	OTP     string `json:"otp,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+}
+
+// Delivery is a queued notification sent by the background worker in async mode
+type Delivery struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	Kind          DeliveryKind    `gorm:"not null"`
+	Payload       DeliveryPayload `gorm:"serializer:json;type:jsonb;not null"`
+	Status        DeliveryStatus  `gorm:"not null;index"`
+	Attempts      int             `gorm:"not null;default:0"`
+	LastError     string
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	SentAt        *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Delivery) TableName() string {
+	return "notification_deliveries"
+}
+
+type DeliveryWorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int // attempts before falling back to Kafka
+}
+
+// DeliveryWorker drains queued deliveries, retrying with backoff until they succeed or
+// MaxAttempts is reached, at which point the Kafka fallback is used.
+type DeliveryWorker struct {
+	db     *gorm.DB
+	client *NotificationClient
+	cfg    DeliveryWorkerConfig
+}
+
+func NewDeliveryWorker(db *gorm.DB, client *NotificationClient, cfg DeliveryWorkerConfig) *DeliveryWorker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	return &DeliveryWorker{db: db, client: client, cfg: cfg}
+}
+
+// Run polls for due deliveries until ctx is cancelled
+func (w *DeliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.processBatch(ctx); err != nil && ctx.Err() == nil {
+				logging.GetLogger().Error("notification delivery worker failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (w *DeliveryWorker) processBatch(ctx context.Context) error {
+	// Skip the round entirely while the breaker is open instead of burning attempts
+	if w.client.breaker.Open() {
+		return nil
+	}
+
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deliveries []Delivery
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, time.Now()).
+			Order("next_attempt_at").
+			Limit(w.cfg.BatchSize).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			if err := tx.Save(w.attempt(ctx, &deliveries[i])).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (w *DeliveryWorker) attempt(ctx context.Context, delivery *Delivery) *Delivery {
+	delivery.Attempts++
+	if delivery.Attempts > 1 {
+		metrics.NotificationRetriesTotal.WithLabelValues(string(delivery.Kind)).Inc()
+	}
+	err := w.client.callOnce(ctx, delivery.Kind, delivery.Payload)
+	if err == nil {
+		now := time.Now()
+		delivery.Status = DeliverySent
+		delivery.SentAt = &now
+		delivery.LastError = ""
+		// One time codes are not kept once delivered
+		delivery.Payload = DeliveryPayload{}
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts < w.cfg.MaxAttempts {
+		delivery.NextAttemptAt = time.Now().Add(exponentialBackoff(delivery.Attempts))
+		return delivery
+	}
+
+	if fallbackErr := w.client.fallback(ctx, delivery.Kind, delivery.Payload); fallbackErr != nil {
+		delivery.Status = DeliveryFailed
+		delivery.LastError = fallbackErr.Error()
+		return delivery
+	}
+	delivery.Status = DeliveryFallback
+	delivery.Payload = DeliveryPayload{}
+	return delivery
+}
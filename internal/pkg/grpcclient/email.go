@@ -7,68 +7,155 @@ import (
 	"math"
 	"ride-sharing/config"
 	"ride-sharing/internal/pkg/constants"
+	"ride-sharing/internal/pkg/events"
 	"ride-sharing/internal/pkg/kafka"
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/templates"
 	"ride-sharing/internal/proto"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"gorm.io/gorm"
 )
 
 type NotificationClient struct {
-	client proto.NotificationServiceClient
-	kafka  *kafka.Producer
-	conn   *grpc.ClientConn
+	client      proto.NotificationServiceClient
+	kafka       *kafka.Producer
+	conn        *grpc.ClientConn
+	breaker     *CircuitBreaker
+	callTimeout time.Duration
+	maxAttempts int
+	templates   *templates.Renderer
+	// db is set in async mode; sends are queued and delivered by the DeliveryWorker
+	db *gorm.DB
 }
 
-func NewNotificationClient(cfg *config.Config, producer *kafka.Producer) (*NotificationClient, error) {
+// NewNotificationClient connects to the notification service. Passing a non-nil db when
+// NOTIFICATION_ASYNC is enabled makes sends return immediately after queuing the delivery.
+func NewNotificationClient(cfg *config.Config, producer *kafka.Producer, db *gorm.DB, renderer *templates.Renderer) (*NotificationClient, error) {
 	conn, err := grpc.NewClient(
 		cfg.Notification.Host+":"+cfg.Notification.Port,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// Propagates the trace context to the notification service through gRPC metadata
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &NotificationClient{
-		client: proto.NewNotificationServiceClient(conn),
-		conn:   conn,
-		kafka:  producer,
-	}, nil
+	client := &NotificationClient{
+		client:      proto.NewNotificationServiceClient(conn),
+		conn:        conn,
+		kafka:       producer,
+		breaker:     NewCircuitBreaker(cfg.Notification.BreakerThreshold, time.Duration(cfg.Notification.BreakerCooldownMs)*time.Millisecond),
+		callTimeout: time.Duration(cfg.Notification.CallTimeoutMs) * time.Millisecond,
+		maxAttempts: cfg.Notification.MaxAttempts,
+		templates:   renderer,
+	}
+	if cfg.Notification.Async {
+		client.db = db
+	}
+	return client, nil
+}
+
+// Ping waits for the connection to the notification service to become ready. An open
+// circuit counts as unavailable even if the transport is up.
+func (n *NotificationClient) Ping(ctx context.Context) error {
+	if n.breaker.Open() {
+		return ErrCircuitOpen
+	}
+
+	n.conn.Connect()
+	for {
+		state := n.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !n.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("notification service %s: %w", strings.ToLower(state.String()), ctx.Err())
+		}
+	}
 }
 
 func (c *NotificationClient) Close() error {
 	return c.conn.Close()
 }
 
-func (n *NotificationClient) SendRegisterEmail(ctx context.Context, to string, otp string) (bool, error) {
-	req := &proto.RegisterEmailRequest{
-		To:  to,
-		Otp: otp,
+func (n *NotificationClient) SendRegisterEmail(ctx context.Context, to string, otp string, locale string) (bool, error) {
+	return n.send(ctx, DeliveryRegisterEmail, DeliveryPayload{To: to, OTP: otp, Locale: locale})
+}
+
+func (n *NotificationClient) SendForgetPasswordEmail(ctx context.Context, to string, otp string, locale string) (bool, error) {
+	return n.send(ctx, DeliveryForgetPasswordEmail, DeliveryPayload{To: to, OTP: otp, Locale: locale})
+}
+
+func (n *NotificationClient) SendLoginCode(ctx context.Context, channel constants.LoginCodeChannel, to string, otp string, locale string) (bool, error) {
+	return n.send(ctx, DeliveryLoginCode, DeliveryPayload{To: to, OTP: otp, Channel: string(channel), Locale: locale})
+}
+
+// deliverySpec holds everything kind specific about a delivery, so a new notification
+// only needs an entry in deliverySpecs and a Send method that calls send
+type deliverySpec struct {
+	template string
+	purpose  constants.OTPType
+	// channel is fixed for the kind; when empty the payload picks it, as login codes do
+	channel constants.LoginCodeChannel
+}
+
+var deliverySpecs = map[DeliveryKind]deliverySpec{
+	DeliveryRegisterEmail:       {template: templates.RegisterOTP, purpose: constants.OTPUserRegister, channel: constants.LoginCodeChannelEmail},
+	DeliveryForgetPasswordEmail: {template: templates.ForgetPasswordOTP, purpose: constants.OTPForgetPassword, channel: constants.LoginCodeChannelEmail},
+	DeliveryLoginCode:           {template: templates.LoginCode, purpose: constants.OTPPasswordless},
+}
+
+func (s deliverySpec) channelFor(payload DeliveryPayload) constants.LoginCodeChannel {
+	if s.channel != "" {
+		return s.channel
 	}
+	return constants.LoginCodeChannel(payload.Channel)
+}
 
-	var lastErr error
-	maxAttempts := 3
+// send queues the delivery in async mode, otherwise it retries the RPC within the
+// caller's context and falls back to Kafka when the attempts run out.
+func (n *NotificationClient) send(ctx context.Context, kind DeliveryKind, payload DeliveryPayload) (bool, error) {
+	if n.db != nil {
+		delivery := &Delivery{
+			ID:            uuid.New(),
+			Kind:          kind,
+			Payload:       payload,
+			Status:        DeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := n.db.WithContext(ctx).Create(delivery).Error; err != nil {
+			return false, fmt.Errorf("failed to queue notification: %w", err)
+		}
+		return true, nil
+	}
 
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		_, err := n.client.SendRegisterEmail(ctx, req)
-		if err == nil {
+	var lastErr error
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		lastErr = n.callOnce(ctx, kind, payload)
+		if lastErr == nil {
 			return true, nil
 		}
-
-		lastErr = err
-
-		if attempt < maxAttempts {
-			time.Sleep(exponentialBackoff(attempt))
+		// No point waiting out the backoff while the circuit is open or the caller has gone
+		if errors.Is(lastErr, ErrCircuitOpen) || attempt == n.maxAttempts {
+			break
+		}
+		if err := sleepContext(ctx, exponentialBackoff(attempt)); err != nil {
+			lastErr = err
+			break
 		}
+		metrics.NotificationRetriesTotal.WithLabelValues(string(kind)).Inc()
 	}
 
 	// Fallback to Kafka
-	kafkaErr := n.kafka.Produce(ctx, string(constants.OTPUserRegister), map[string]string{
-		"type": string(constants.OTPUserRegister),
-		"to":   to,
-		"otp":  otp,
-	})
+	kafkaErr := n.fallback(ctx, kind, payload)
 
 	if kafkaErr != nil {
 		return false, errors.Join(
@@ -80,43 +167,82 @@ func (n *NotificationClient) SendRegisterEmail(ctx context.Context, to string, o
 	return true, nil
 }
 
-func (n *NotificationClient) SendForgetPasswordEmail(ctx context.Context, to string, otp string) (bool, error) {
-	req := &proto.ForgetPasswordEmailRequest{
-		To:  to,
-		Otp: otp,
+// callOnce renders the message in the recipient's locale and makes a single
+// SendNotification RPC through the circuit breaker with its own deadline.
+func (n *NotificationClient) callOnce(ctx context.Context, kind DeliveryKind, payload DeliveryPayload) error {
+	spec, ok := deliverySpecs[kind]
+	if !ok {
+		return fmt.Errorf("unknown notification kind %q", kind)
 	}
 
-	var lastErr error
-	maxAttempts := 3
+	rendered, err := n.templates.Render(spec.template, payload.Locale, 0, map[string]string{"OTP": payload.OTP})
+	if err != nil {
+		return err
+	}
 
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		_, err := n.client.SendForgetPasswordEmail(ctx, req)
-		if err == nil {
-			return true, nil
-		}
+	err = n.breaker.Execute(func() error {
+		callCtx, cancel := context.WithTimeout(ctx, n.callTimeout)
+		defer cancel()
+
+		_, err := n.client.SendNotification(callCtx, &proto.NotificationRequest{
+			Channel:         string(spec.channelFor(payload)),
+			To:              payload.To,
+			Subject:         rendered.Subject,
+			BodyText:        rendered.Text,
+			BodyHtml:        rendered.HTML,
+			Template:        rendered.Name,
+			TemplateVersion: int32(rendered.Version),
+			Locale:          rendered.Locale,
+		})
+		return err
+	})
+
+	result := metrics.Result(err)
+	if errors.Is(err, ErrCircuitOpen) {
+		result = "circuit_open"
+	}
+	metrics.NotificationRequestsTotal.WithLabelValues(string(kind), result).Inc()
+	return err
+}
 
-		lastErr = err
+func (n *NotificationClient) fallback(ctx context.Context, kind DeliveryKind, payload DeliveryPayload) (err error) {
+	defer func() {
+		metrics.NotificationFallbacksTotal.WithLabelValues(string(kind), metrics.Result(err)).Inc()
+	}()
 
-		if attempt < maxAttempts {
-			time.Sleep(exponentialBackoff(attempt))
-		}
+	spec, ok := deliverySpecs[kind]
+	if !ok {
+		return fmt.Errorf("no kafka fallback for notification kind %q", kind)
 	}
+	return n.publishOTPFallback(ctx, spec.purpose, spec.channelFor(payload), payload)
+}
 
-	// Fallback to Kafka
-	kafkaErr := n.kafka.Produce(ctx, string(constants.OTPUserRegister), map[string]string{
-		"type": string(constants.OTPUserRegister),
-		"to":   to,
-		"otp":  otp,
+// publishOTPFallback hands the code to the notification service over Kafka as a typed,
+// schema validated event. The producer is bound to KAFKA_TOPIC, so the topic is left empty.
+func (n *NotificationClient) publishOTPFallback(ctx context.Context, purpose constants.OTPType, channel constants.LoginCodeChannel, payload DeliveryPayload) error {
+	msg, err := events.NewMessage(ctx, events.NotificationOTPRequested, payload.To, events.NotificationOTPRequestedData{
+		Purpose: string(purpose),
+		Channel: string(channel),
+		To:      payload.To,
+		OTP:     payload.OTP,
+		Locale:  payload.Locale,
 	})
-
-	if kafkaErr != nil {
-		return false, errors.Join(
-			fmt.Errorf("gRPC attempts failed: %w", lastErr),
-			fmt.Errorf("kafka fallback failed: %w", kafkaErr),
-		)
+	if err != nil {
+		return err
 	}
+	return n.kafka.Publish(ctx, msg)
+}
 
-	return true, nil
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func exponentialBackoff(attempt int) time.Duration {
@@ -0,0 +1,73 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidDeviceToken means the push provider rejected the token for good and it should be removed
+var ErrInvalidDeviceToken = errors.New("device token is invalid or unregistered")
+
+// Error codes the notification service returns in StandardResponse for dead tokens
+var invalidTokenCodes = map[string]struct{}{
+	"INVALID_TOKEN":      {},
+	"UNREGISTERED_TOKEN": {},
+}
+
+// SendPush delivers a push notification to a single device. It is a single attempt through
+// the circuit breaker; callers decide whether a failed push is worth retrying.
+func (n *NotificationClient) SendPush(ctx context.Context, deviceToken, title, body string, data map[string]string) (err error) {
+	defer func() {
+		result := metrics.Result(err)
+		switch {
+		case errors.Is(err, ErrInvalidDeviceToken):
+			result = "invalid_token"
+		case errors.Is(err, ErrCircuitOpen):
+			result = "circuit_open"
+		}
+		metrics.NotificationRequestsTotal.WithLabelValues("PUSH", result).Inc()
+	}()
+
+	var res *proto.StandardResponse
+	invalidToken := false
+
+	err = n.breaker.Execute(func() error {
+		callCtx, cancel := context.WithTimeout(ctx, n.callTimeout)
+		defer cancel()
+
+		var err error
+		res, err = n.client.SendPush(callCtx, &proto.PushRequest{
+			DeviceToken: deviceToken,
+			Title:       title,
+			Body:        body,
+			Data:        data,
+		})
+		// A rejected token says nothing about the health of the notification service
+		if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument {
+			invalidToken = true
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if invalidToken {
+		return ErrInvalidDeviceToken
+	}
+
+	if !res.GetSuccess() {
+		if _, invalid := invalidTokenCodes[res.GetError().GetErrorCode()]; invalid {
+			return ErrInvalidDeviceToken
+		}
+		return fmt.Errorf("push rejected: %s", res.GetMessage())
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package grpcserver
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	riderRepository "ride-sharing/internal/domains/riders/repository"
+	userRepository "ride-sharing/internal/domains/users/repository"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/proto"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthServer exposes token validation and identity lookups to other services
+type AuthServer struct {
+	proto.UnimplementedAuthServiceServer
+	tokenService  *auth.TokenService
+	userProviders map[auth.UserType]auth.UserProvider
+	userRepo      userRepository.UserRepository
+	riderRepo     riderRepository.RiderRepository
+}
+
+func NewAuthServer(
+	tokenService *auth.TokenService,
+	userProviders map[auth.UserType]auth.UserProvider,
+	userRepo userRepository.UserRepository,
+	riderRepo riderRepository.RiderRepository,
+) *AuthServer {
+	return &AuthServer{
+		tokenService:  tokenService,
+		userProviders: userProviders,
+		userRepo:      userRepo,
+		riderRepo:     riderRepo,
+	}
+}
+
+// ValidateToken applies the same checks as the HTTP AuthMiddleware to an access token
+func (s *AuthServer) ValidateToken(ctx context.Context, req *proto.ValidateTokenRequest) (*proto.ValidateTokenResponse, error) {
+	claims, err := s.tokenService.ValidateAccessToken(req.GetAccessToken())
+	if err != nil {
+		return &proto.ValidateTokenResponse{Valid: false, Reason: "invalid token"}, nil
+	}
+
+	reason, err := s.checkSession(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return &proto.ValidateTokenResponse{Valid: false, Reason: reason}, nil
+	}
+
+	res := &proto.ValidateTokenResponse{
+		Valid:    true,
+		UserId:   claims.UserID,
+		UserType: string(claims.UserType),
+	}
+	if claims.ExpiresAt != nil {
+		res.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IsImpersonated() {
+		res.ImpersonatorId = claims.Actor.UserID
+	}
+	return res, nil
+}
+
+func (s *AuthServer) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.UserIdentity, error) {
+	if _, err := uuid.Parse(req.GetUserId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &proto.UserIdentity{
+		Id:        user.ID.String(),
+		Email:     user.Email,
+		FullName:  user.FullName,
+		Phone:     user.Phone,
+		Active:    user.Active,
+		CreatedAt: user.CreatedAt.Unix(),
+	}, nil
+}
+
+func (s *AuthServer) GetRider(ctx context.Context, req *proto.GetRiderRequest) (*proto.RiderIdentity, error) {
+	if _, err := uuid.Parse(req.GetRiderId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rider id")
+	}
+
+	rider, err := s.riderRepo.GetByID(ctx, req.GetRiderId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &proto.RiderIdentity{
+		Id:             rider.ID.String(),
+		Email:          rider.Email,
+		FullName:       rider.FullName,
+		Phone:          rider.Phone,
+		VehicleType:    rider.VehicleType,
+		IsApproved:     rider.IsApproved,
+		ApprovalStatus: rider.ApprovalStatus,
+		Rating:         rider.Rating,
+		OnlineStatus:   rider.OnlineStatus,
+	}, nil
+}
+
+// IntrospectSession reports whether an access or refresh token is still active, in the
+// spirit of RFC 7662. Inactive tokens are not an error, they just report active=false.
+func (s *AuthServer) IntrospectSession(ctx context.Context, req *proto.IntrospectSessionRequest) (*proto.IntrospectSessionResponse, error) {
+	claims, err := s.tokenService.ValidateAccessToken(req.GetToken())
+	if err != nil {
+		claims, err = s.tokenService.ValidateRefreshToken(req.GetToken())
+		if err != nil {
+			return &proto.IntrospectSessionResponse{Active: false}, nil
+		}
+	}
+
+	reason, err := s.checkSession(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return &proto.IntrospectSessionResponse{Active: false}, nil
+	}
+
+	res := &proto.IntrospectSessionResponse{
+		Active:    true,
+		TokenType: claims.TokenType,
+		UserId:    claims.UserID,
+		UserType:  string(claims.UserType),
+	}
+	if claims.IssuedAt != nil {
+		res.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		res.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IsImpersonated() {
+		res.ImpersonatorId = claims.Actor.UserID
+	}
+	return res, nil
+}
+
+// checkSession verifies the account behind the token still exists, is not suspended and has
+// not changed its password since the token was issued, and that the admin behind an
+// impersonation token may still act. A non-empty reason means the session is invalid.
+func (s *AuthServer) checkSession(ctx context.Context, claims *auth.TokenClaims) (string, error) {
+	if claims.PasswordExpired {
+		return "password expired", nil
+	}
+
+	provider, exists := s.userProviders[claims.UserType]
+	if !exists {
+		return "invalid user type", nil
+	}
+
+	account, err := provider.GetByID(ctx, claims.UserID, claims.UserType)
+	if err != nil {
+		var appErr *errors.AppError
+		if stdErrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			return "account not found", nil
+		}
+		return "", toStatus(err)
+	}
+
+	identity, ok := account.(auth.Identity)
+	if !ok || identity.GetPasswordChangedAt() == nil {
+		return "", status.Error(codes.Internal, "account is not of expected type")
+	}
+	if identity.GetSuspendedAt() != nil {
+		return "account suspended", nil
+	}
+	if time.Unix(0, claims.PasswordChangedAt).Before(*identity.GetPasswordChangedAt()) {
+		return "password changed", nil
+	}
+
+	// Like the HTTP middleware, an impersonation token dies with its admin
+	if claims.IsImpersonated() {
+		reason, err := auth.CheckActor(ctx, s.userProviders, claims.Actor)
+		if err != nil {
+			return "", toStatus(err)
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+
+	return "", nil
+}
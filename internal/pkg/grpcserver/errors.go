@@ -0,0 +1,35 @@
+package grpcserver
+
+import (
+	stdErrors "errors"
+
+	"ride-sharing/internal/pkg/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps application errors to their closest gRPC status code
+func toStatus(err error) error {
+	var appErr *errors.AppError
+	if !stdErrors.As(err, &appErr) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	switch appErr.Type {
+	case errors.ErrorTypeValidation, errors.ErrorTypeVerification:
+		return status.Error(codes.InvalidArgument, appErr.Message)
+	case errors.ErrorTypeNotFound:
+		return status.Error(codes.NotFound, appErr.Message)
+	case errors.ErrorTypeConflict:
+		return status.Error(codes.AlreadyExists, appErr.Message)
+	case errors.ErrorTypeUnauthorized:
+		return status.Error(codes.Unauthenticated, appErr.Message)
+	case errors.ErrorTypeForbidden:
+		return status.Error(codes.PermissionDenied, appErr.Message)
+	case errors.ErrorTypeTooMany:
+		return status.Error(codes.ResourceExhausted, appErr.Message)
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
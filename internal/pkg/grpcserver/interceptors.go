@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/logging"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadataKey carries the calling service's API key
+const APIKeyMetadataKey = "x-api-key"
+
+type identityKey struct{}
+
+// IdentityFromContext returns the service account authenticated by AuthInterceptor
+func IdentityFromContext(ctx context.Context) (*auth.APIKeyIdentity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*auth.APIKeyIdentity)
+	return identity, ok
+}
+
+// RequestIDInterceptor propagates request and correlation IDs from incoming metadata,
+// generating them when absent, and echoes them back in the response header
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		requestID := firstValue(md, logging.RequestIDKey)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		correlationID := firstValue(md, logging.CorrelationID)
+		if correlationID == "" {
+			correlationID = uuid.New().String()
+		}
+
+		ctx = context.WithValue(ctx, logging.RequestIDKey, requestID)
+		ctx = context.WithValue(ctx, logging.CorrelationID, correlationID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			logging.RequestIDKey, requestID,
+			logging.CorrelationID, correlationID,
+		))
+
+		return handler(ctx, req)
+	}
+}
+
+// LoggingInterceptor logs every call with its status code and latency
+func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+
+		logger := logging.GetLogger().WithContext(ctx)
+		switch code {
+		case codes.OK:
+			logger.Info("grpc request processed", fields...)
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+			logger.Error("grpc server error", fields...)
+		default:
+			logger.Warn("grpc client error", fields...)
+		}
+
+		return resp, err
+	}
+}
+
+// AuthInterceptor requires callers to present an API key granted the given scope
+func AuthInterceptor(apiKeyAuth auth.APIKeyAuthenticator, scope string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		apiKey := firstValue(md, APIKeyMetadataKey)
+		if apiKey == "" {
+			return nil, status.Error(codes.Unauthenticated, "api key required")
+		}
+
+		identity, err := apiKeyAuth.AuthenticateAPIKey(ctx, apiKey)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		if !identity.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "api key missing required scope: "+scope)
+		}
+
+		return handler(context.WithValue(ctx, identityKey{}, identity), req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
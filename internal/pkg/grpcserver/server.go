@@ -0,0 +1,23 @@
+package grpcserver
+
+import (
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/proto"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// NewServer builds the gRPC server with tracing, request ID, logging and API key auth interceptors
+func NewServer(authServer *AuthServer, apiKeyAuth auth.APIKeyAuthenticator) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			RequestIDInterceptor(),
+			LoggingInterceptor(),
+			AuthInterceptor(apiKeyAuth, auth.ScopeAuthIntrospect),
+		),
+	)
+	proto.RegisterAuthServiceServer(server, authServer)
+	return server
+}
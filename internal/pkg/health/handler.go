@@ -0,0 +1,36 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Liveness only reports that the process is serving requests; it never checks
+// dependencies, so an outage elsewhere does not get the pod restarted
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusUp})
+}
+
+// Readiness answers 503 when a critical dependency is down or the server is shutting down
+func Readiness(checker *Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checker.Draining() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": StatusDown, "shutting_down": true})
+			return
+		}
+		report := checker.Run(c.Request.Context())
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
+// Dependencies always answers 200 with the full report, for dashboards and debugging
+func Dependencies(checker *Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, checker.Run(c.Request.Context()))
+	}
+}
@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDegraded Status = "degraded" // only non-critical dependencies are down
+)
+
+// CheckFunc returns nil when the dependency is reachable
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// DependencyStatus is the outcome of a single check
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report aggregates every check. The overall status is down only when a critical
+// dependency is down, so traffic keeps flowing while optional ones recover.
+type Report struct {
+	Status       Status             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// Checker runs the registered dependency checks concurrently, each bounded by the timeout
+type Checker struct {
+	timeout  time.Duration
+	checks   []check
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a check. A failing critical check marks the service as not ready.
+// It must be called before the checker is used.
+func (c *Checker) Register(name string, critical bool, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// Drain makes readiness fail from now on, so traffic moves away before the server stops
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]DependencyStatus, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, chk)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, CheckedAt: time.Now().UTC(), Dependencies: results}
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, chk check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := chk.fn(ctx)
+	result := DependencyStatus{
+		Name:      chk.name,
+		Status:    StatusUp,
+		Critical:  chk.critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
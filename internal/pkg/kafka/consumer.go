@@ -0,0 +1,379 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/tracing"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// Headers used by the consumer to route and retry messages
+const (
+	HeaderEventType      = "event-type"
+	HeaderCorrelationID  = "correlation-id"
+	HeaderRetryAttempt   = "retry-attempt"
+	HeaderRetryNotBefore = "retry-not-before"
+	HeaderOriginalTopic  = "original-topic"
+	HeaderLastError      = "last-error"
+	// HeaderDeadLetterReason says why a message reached the dead-letter topic
+	HeaderDeadLetterReason = "dead-letter-reason"
+
+	retryTopicSuffix = ".retry"
+	dlqTopicSuffix   = ".dlq"
+)
+
+// Handler processes a single message. Returning an error sends the message to the retry
+// topic, and to the dead-letter topic once MaxAttempts is reached. Errors wrapped with
+// Permanent go to the dead-letter topic straight away.
+type Handler func(ctx context.Context, msg Message) error
+
+// Dead-letter reasons
+const (
+	deadLetterMaxAttempts  = "max-attempts"
+	deadLetterNonRetryable = "non-retryable"
+)
+
+// permanentError marks a failure that retrying cannot fix, such as a payload that does
+// not decode or validate
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as non-retryable, so the message is dead-lettered without retries
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type ConsumerConfig struct {
+	Brokers      []string
+	GroupID      string
+	Topics       []string
+	Concurrency  int           // workers per reader; each partition is always handled by the same worker
+	MaxAttempts  int           // total deliveries before a message is dead-lettered
+	RetryBackoff time.Duration // base delay, doubled for every further attempt
+	DrainTimeout time.Duration // how long in-flight messages may take to finish on shutdown
+}
+
+// Consumer reads a consumer group, dispatches messages to the handler registered for
+// their event type and commits offsets in order per partition.
+type Consumer struct {
+	cfg ConsumerConfig
+	// The main topics and the retry topics are read separately, each with its own workers,
+	// so a retry waiting out its backoff never holds up a partition of a main topic
+	readers  []*kafka.Reader
+	producer *Producer
+	handlers map[string]Handler
+}
+
+// NewConsumer subscribes to the configured topics and their retry topics. The producer is
+// used to forward failed messages and must not be bound to a single topic.
+func NewConsumer(cfg ConsumerConfig, producer *Producer) *Consumer {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = 30 * time.Second
+	}
+
+	retryTopics := make([]string, 0, len(cfg.Topics))
+	for _, topic := range cfg.Topics {
+		retryTopics = append(retryTopics, topic+retryTopicSuffix)
+	}
+
+	// Both readers join the same group; partitions are assigned per topic among the
+	// members subscribed to it, so committed offsets carry over
+	newReader := func(topics []string) *kafka.Reader {
+		return kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     cfg.Brokers,
+			GroupID:     cfg.GroupID,
+			GroupTopics: topics,
+			StartOffset: kafka.FirstOffset,
+		})
+	}
+
+	return &Consumer{
+		cfg:      cfg,
+		readers:  []*kafka.Reader{newReader(cfg.Topics), newReader(retryTopics)},
+		producer: producer,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle registers the handler for an event type. It must be called before Run.
+func (c *Consumer) Handle(eventType string, handler Handler) {
+	c.handlers[eventType] = handler
+}
+
+// Run consumes until ctx is cancelled or a fetch fails, then stops fetching, lets
+// in-flight messages finish within DrainTimeout and closes the readers.
+func (c *Consumer) Run(ctx context.Context) error {
+	logger := logging.GetLogger()
+
+	// Handlers keep running on their own context so cancelling ctx does not abort them mid-way
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	// A failing reader stops the other one too
+	fetchCtx, stopFetching := context.WithCancel(ctx)
+	defer stopFetching()
+
+	var workers, fetchers sync.WaitGroup
+	errs := make([]error, len(c.readers))
+	for i, reader := range c.readers {
+		queues := c.startWorkers(workCtx, reader, &workers)
+		fetchers.Add(1)
+		go func() {
+			defer fetchers.Done()
+			if errs[i] = c.fetch(fetchCtx, reader, queues); errs[i] != nil {
+				stopFetching()
+			}
+		}()
+	}
+	fetchers.Wait()
+
+	drained := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(c.cfg.DrainTimeout):
+		logger.Warn("kafka consumer drain timed out, uncommitted messages will be redelivered")
+		cancelWork()
+		<-drained
+	}
+
+	for _, reader := range c.readers {
+		if err := reader.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// startWorkers starts the workers for one reader and returns their queues
+func (c *Consumer) startWorkers(ctx context.Context, reader *kafka.Reader, workers *sync.WaitGroup) []chan kafka.Message {
+	queues := make([]chan kafka.Message, c.cfg.Concurrency)
+	for i := range queues {
+		queues[i] = make(chan kafka.Message)
+		workers.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer workers.Done()
+			for msg := range queue {
+				c.process(ctx, reader, msg)
+			}
+		}(queues[i])
+	}
+	return queues
+}
+
+// fetch hands messages to the worker owning their partition until ctx is cancelled or
+// fetching fails, then closes the queues
+func (c *Consumer) fetch(ctx context.Context, reader *kafka.Reader, queues []chan kafka.Message) error {
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+	}()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				return fmt.Errorf("kafka fetch failed: %w", err)
+			}
+			return nil
+		}
+		queue := queues[msg.Partition%len(queues)]
+		select {
+		case queue <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, reader *kafka.Reader, raw kafka.Message) {
+	logger := logging.GetLogger()
+	msg := fromKafkaMessage(raw)
+	if correlationID := msg.Headers[HeaderCorrelationID]; correlationID != "" {
+		ctx = context.WithValue(ctx, logging.CorrelationID, correlationID)
+	}
+
+	if err := c.waitForRetry(ctx, msg); err != nil {
+		return
+	}
+
+	ctx, span := startProcessSpan(ctx, msg)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	handler, ok := c.handlers[msg.Headers[HeaderEventType]]
+	if !ok {
+		// Not ours to handle; commit so it is not redelivered forever
+		c.commit(ctx, reader, raw)
+		return
+	}
+
+	err = safeHandle(ctx, handler, msg)
+	metrics.KafkaConsumedTotal.WithLabelValues(raw.Topic, msg.Headers[HeaderEventType], metrics.Result(err)).Inc()
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("kafka handler failed",
+			zap.String("topic", raw.Topic),
+			zap.String("event_type", msg.Headers[HeaderEventType]),
+			zap.Int("attempt", attempt(msg)),
+			zap.Error(err),
+		)
+		if !c.forward(ctx, msg, err) {
+			return
+		}
+	}
+
+	c.commit(ctx, reader, raw)
+}
+
+// waitForRetry delays messages from a retry topic until their backoff has elapsed. Only
+// workers of the retry reader ever wait here.
+func (c *Consumer) waitForRetry(ctx context.Context, msg Message) error {
+	notBefore, err := strconv.ParseInt(msg.Headers[HeaderRetryNotBefore], 10, 64)
+	if err != nil {
+		return nil
+	}
+	delay := time.Until(time.UnixMilli(notBefore))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// forward moves a failed message to the retry or dead-letter topic. Permanent failures skip
+// the retries. It keeps trying until
+// the broker accepts it, since committing without forwarding would lose the message.
+func (c *Consumer) forward(ctx context.Context, msg Message, handlerErr error) bool {
+	next := attempt(msg) + 1
+	origin := msg.Headers[HeaderOriginalTopic]
+	if origin == "" {
+		origin = msg.Topic
+	}
+
+	headers := make(map[string]string, len(msg.Headers)+4)
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[HeaderOriginalTopic] = origin
+	headers[HeaderRetryAttempt] = strconv.Itoa(next)
+	headers[HeaderLastError] = handlerErr.Error()
+
+	topic := origin + dlqTopicSuffix
+	switch {
+	case IsPermanent(handlerErr):
+		delete(headers, HeaderRetryNotBefore)
+		headers[HeaderDeadLetterReason] = deadLetterNonRetryable
+	case next < c.cfg.MaxAttempts:
+		topic = origin + retryTopicSuffix
+		headers[HeaderRetryNotBefore] = strconv.FormatInt(time.Now().Add(c.backoff(next)).UnixMilli(), 10)
+	default:
+		delete(headers, HeaderRetryNotBefore)
+		headers[HeaderDeadLetterReason] = deadLetterMaxAttempts
+	}
+
+	out := Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers}
+	for wait := c.cfg.RetryBackoff; ; wait *= 2 {
+		err := c.producer.Publish(ctx, out)
+		if err == nil {
+			return true
+		}
+		logging.GetLogger().Error("failed to forward kafka message", zap.String("topic", topic), zap.Error(err))
+
+		if wait > time.Minute {
+			wait = time.Minute
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (c *Consumer) commit(ctx context.Context, reader *kafka.Reader, raw kafka.Message) {
+	if err := reader.CommitMessages(ctx, raw); err != nil {
+		logging.GetLogger().Error("failed to commit kafka offset",
+			zap.String("topic", raw.Topic),
+			zap.Int("partition", raw.Partition),
+			zap.Int64("offset", raw.Offset),
+			zap.Error(err),
+		)
+	}
+}
+
+func (c *Consumer) backoff(attempt int) time.Duration {
+	return c.cfg.RetryBackoff * time.Duration(1<<min(attempt-1, 10))
+}
+
+// safeHandle turns a handler panic into an error so one bad message cannot stop the worker
+func safeHandle(ctx context.Context, handler Handler, msg Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprint("handler panic: ", r))
+		}
+	}()
+	return handler(ctx, msg)
+}
+
+func attempt(msg Message) int {
+	n, _ := strconv.Atoi(msg.Headers[HeaderRetryAttempt])
+	return n
+}
+
+func fromKafkaMessage(raw kafka.Message) Message {
+	headers := make(map[string]string, len(raw.Headers))
+	for _, header := range raw.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	return Message{
+		Topic:   raw.Topic,
+		Key:     string(raw.Key),
+		Value:   raw.Value,
+		Headers: headers,
+	}
+}
@@ -1,26 +1,41 @@
 package kafka
 
 import (
+	"time"
+
 	"ride-sharing/config"
 
 	"github.com/segmentio/kafka-go"
 )
 
 func NewProducerFromAppConfig(cfg *config.Config) *Producer {
-	var balancer kafka.Balancer
+	return NewProducer(ProducerConfig{
+		Brokers:  cfg.Kafka.Brokers,
+		Topic:    cfg.Kafka.Topic,
+		Balancer: balancerFromConfig(cfg.Kafka.Balancer),
+	})
+}
 
-	switch cfg.Kafka.Balancer {
+// NewOutboxProducerFromAppConfig builds the producer used by the outbox relay. Messages
+// carry their own topic and are hash partitioned so events for one key stay ordered. Every
+// write waits for its acknowledgement, so batches are sized to hold a whole relay batch and
+// are not held back waiting for more messages.
+func NewOutboxProducerFromAppConfig(cfg *config.Config) *Producer {
+	return NewProducer(ProducerConfig{
+		Brokers:      cfg.Kafka.Brokers,
+		Balancer:     &kafka.Hash{},
+		BatchSize:    cfg.Outbox.BatchSize,
+		BatchTimeout: time.Millisecond,
+	})
+}
+
+func balancerFromConfig(name string) kafka.Balancer {
+	switch name {
 	case "round-robin":
-		balancer = &kafka.RoundRobin{}
+		return &kafka.RoundRobin{}
 	case "hash":
-		balancer = &kafka.Hash{}
+		return &kafka.Hash{}
 	default:
-		balancer = &kafka.LeastBytes{}
+		return &kafka.LeastBytes{}
 	}
-
-	return NewProducer(ProducerConfig{
-		Brokers:  cfg.Kafka.Brokers,
-		Topic:    cfg.Kafka.Topic,
-		Balancer: balancer,
-	})
 }
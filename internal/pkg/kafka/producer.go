@@ -3,30 +3,58 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/tracing"
+
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Producer struct {
-	writer *kafka.Writer
+	writer  *kafka.Writer
+	brokers []string
 }
 
 type ProducerConfig struct {
 	Brokers  []string
-	Topic    string
+	Topic    string // leave empty to set the topic per message
 	Balancer kafka.Balancer
+	// Async returns before delivery and drops errors; only for truly disposable messages
+	Async bool
+	// BatchSize and BatchTimeout bound how long a partially filled batch waits before it is
+	// sent. Defaults are 100 messages and 50ms.
+	BatchSize    int
+	BatchTimeout time.Duration
+}
+
+// Message is a single record with an explicit topic and headers
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
 }
 
 func NewProducer(cfg ProducerConfig) *Producer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.BatchTimeout <= 0 {
+		cfg.BatchTimeout = 50 * time.Millisecond
+	}
 	return &Producer{
+		brokers: cfg.Brokers,
 		writer: &kafka.Writer{
 			Addr:         kafka.TCP(cfg.Brokers...),
 			Topic:        cfg.Topic,
 			Balancer:     cfg.Balancer,
-			BatchTimeout: 50 * time.Millisecond,
-			Async:        true,
+			BatchSize:    cfg.BatchSize,
+			BatchTimeout: cfg.BatchTimeout,
+			Async:        cfg.Async,
 			RequiredAcks: kafka.RequireOne,
 		},
 	}
@@ -36,22 +64,129 @@ func (p *Producer) Close() error {
 	return p.writer.Close()
 }
 
-func (p *Producer) Produce(ctx context.Context, key string, value interface{}) error {
+// Ping succeeds once any broker answers a metadata request
+func (p *Producer) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, broker := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_, err = conn.Brokers()
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return errors.New("no kafka brokers configured")
+	}
+	return lastErr
+}
+
+func (p *Producer) Produce(ctx context.Context, key string, value interface{}) (err error) {
 	jsonValue, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
+	ctx, span, headers := startPublishSpan(ctx, p.writer.Topic, nil)
+	defer func() { tracing.End(span, err) }()
+
 	err = p.writer.WriteMessages(ctx,
 		kafka.Message{
-			Key:   []byte(key),
-			Value: jsonValue,
-			Time:  time.Now(),
+			Key:     []byte(key),
+			Value:   jsonValue,
+			Headers: toKafkaHeaders(headers),
+			Time:    time.Now(),
 		},
 	)
+	metrics.KafkaProducedTotal.WithLabelValues(p.writer.Topic, metrics.Result(err)).Inc()
 	if err != nil {
 		return fmt.Errorf("kafka produce failed: %w", err)
 	}
 
 	return nil
 }
+
+// Publish writes a single message and waits for the broker acknowledgement
+func (p *Producer) Publish(ctx context.Context, msg Message) (err error) {
+	topic := msg.Topic
+	if topic == "" {
+		topic = p.writer.Topic
+	}
+
+	ctx, span, headers := startPublishSpan(ctx, topic, msg.Headers)
+	defer func() { tracing.End(span, err) }()
+
+	err = p.writer.WriteMessages(ctx,
+		kafka.Message{
+			Topic:   msg.Topic,
+			Key:     []byte(msg.Key),
+			Value:   msg.Value,
+			Headers: toKafkaHeaders(headers),
+			Time:    time.Now(),
+		},
+	)
+	metrics.KafkaProducedTotal.WithLabelValues(topic, metrics.Result(err)).Inc()
+	if err != nil {
+		return fmt.Errorf("kafka publish failed: %w", err)
+	}
+
+	return nil
+}
+
+// PublishBatch writes msgs in one call and waits for the broker acknowledgements. The
+// returned slice holds the error of each message, nil for those that were written.
+func (p *Producer) PublishBatch(ctx context.Context, msgs []Message) []error {
+	spans := make([]trace.Span, len(msgs))
+	records := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		topic := msg.Topic
+		if topic == "" {
+			topic = p.writer.Topic
+		}
+		var headers map[string]string
+		_, spans[i], headers = startPublishSpan(ctx, topic, msg.Headers)
+		records[i] = kafka.Message{
+			Topic:   msg.Topic,
+			Key:     []byte(msg.Key),
+			Value:   msg.Value,
+			Headers: toKafkaHeaders(headers),
+			Time:    time.Now(),
+		}
+	}
+
+	errs := make([]error, len(msgs))
+	err := p.writer.WriteMessages(ctx, records...)
+	// WriteErrors reports each message separately, anything else failed the whole call
+	var writeErrs kafka.WriteErrors
+	perMessage := errors.As(err, &writeErrs)
+	for i, msg := range msgs {
+		errs[i] = err
+		if perMessage {
+			errs[i] = writeErrs[i]
+		}
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("kafka publish failed: %w", errs[i])
+		}
+
+		topic := msg.Topic
+		if topic == "" {
+			topic = p.writer.Topic
+		}
+		metrics.KafkaProducedTotal.WithLabelValues(topic, metrics.Result(errs[i])).Inc()
+		tracing.End(spans[i], errs[i])
+	}
+	return errs
+}
+
+func toKafkaHeaders(headers map[string]string) []kafka.Header {
+	out := make([]kafka.Header, 0, len(headers))
+	for key, value := range headers {
+		out = append(out, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	return out
+}
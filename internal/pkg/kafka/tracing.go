@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"context"
+
+	"ride-sharing/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startPublishSpan starts a producer span and returns headers carrying its traceparent.
+// Without an active span in ctx the span continues the trace already in headers, which is
+// how events written to the outbox during a request stay linked to it when relayed later.
+func startPublishSpan(ctx context.Context, topic string, headers map[string]string) (context.Context, trace.Span, map[string]string) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = tracing.Extract(ctx, headers)
+	}
+	ctx, span := tracing.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation", "publish"),
+			attribute.String("messaging.destination.name", topic),
+		),
+	)
+
+	out := make(map[string]string, len(headers)+2)
+	for key, value := range headers {
+		out[key] = value
+	}
+	tracing.Inject(ctx, out)
+	return ctx, span, out
+}
+
+// startProcessSpan starts a consumer span continuing the trace of the producer
+func startProcessSpan(ctx context.Context, msg Message) (context.Context, trace.Span) {
+	ctx = tracing.Extract(ctx, msg.Headers)
+	return tracing.Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation", "process"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.String("messaging.kafka.message.key", msg.Key),
+			attribute.String("event.type", msg.Headers[HeaderEventType]),
+		),
+	)
+}
@@ -0,0 +1,150 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"ride-sharing/internal/pkg/logging"
+
+	"go.uber.org/zap"
+)
+
+// Stage orders shutdown hooks. Stages run in ascending order, and background workers are
+// stopped between StageServers and StageFlush so nothing they use is closed under them.
+type Stage int
+
+const (
+	// StageServers stops accepting work and drains in-flight requests
+	StageServers Stage = iota
+	// StageFlush flushes buffered output such as Kafka writers
+	StageFlush
+	// StageClose releases connections to Postgres, Redis and other services
+	StageClose
+)
+
+// Config bounds each shutdown step; the context passed to Shutdown caps the total
+type Config struct {
+	DrainTimeout  time.Duration // for StageServers, i.e. finishing in-flight requests
+	WorkerTimeout time.Duration // for background workers to return
+	CloseTimeout  time.Duration // for StageFlush and StageClose together
+}
+
+type hook struct {
+	name  string
+	stage Stage
+	stop  func(ctx context.Context) error
+}
+
+// Manager runs background workers and stops every registered component in order
+type Manager struct {
+	cfg Config
+
+	workerCtx     context.Context
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+
+	mu       sync.Mutex
+	hooks    []hook
+	stopping chan struct{}
+	once     sync.Once
+}
+
+func New(cfg Config) *Manager {
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = 20 * time.Second
+	}
+	if cfg.WorkerTimeout <= 0 {
+		cfg.WorkerTimeout = 10 * time.Second
+	}
+	if cfg.CloseTimeout <= 0 {
+		cfg.CloseTimeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:           cfg,
+		workerCtx:     ctx,
+		cancelWorkers: cancel,
+		stopping:      make(chan struct{}),
+	}
+}
+
+// Go runs a background worker until shutdown cancels its context. Shutdown waits for it to return.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		if err := run(m.workerCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logging.GetLogger().Error("background worker stopped", zap.String("worker", name), zap.Error(err))
+		}
+	}()
+}
+
+// OnStop registers a shutdown hook. Hooks in the same stage run in reverse registration
+// order, so a component registered after its dependencies is stopped before them.
+func (m *Manager) OnStop(stage Stage, name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stage: stage, stop: stop})
+}
+
+// Stopping is closed as soon as Shutdown starts
+func (m *Manager) Stopping() <-chan struct{} {
+	return m.stopping
+}
+
+// Shutdown runs the server hooks, stops the workers, then runs the flush and close hooks.
+// A step that overruns its timeout is logged and the remaining steps still run.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() { close(m.stopping) })
+
+	m.mu.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	var errs []error
+	drainCtx, cancel := context.WithTimeout(ctx, m.cfg.DrainTimeout)
+	errs = append(errs, m.runStage(drainCtx, hooks, StageServers)...)
+	cancel()
+
+	m.cancelWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(stopped)
+	}()
+	workerCtx, cancel := context.WithTimeout(ctx, m.cfg.WorkerTimeout)
+	select {
+	case <-stopped:
+	case <-workerCtx.Done():
+		errs = append(errs, fmt.Errorf("background workers did not stop in time: %w", workerCtx.Err()))
+	}
+	cancel()
+
+	closeCtx, cancel := context.WithTimeout(ctx, m.cfg.CloseTimeout)
+	defer cancel()
+	errs = append(errs, m.runStage(closeCtx, hooks, StageFlush)...)
+	errs = append(errs, m.runStage(closeCtx, hooks, StageClose)...)
+	return errors.Join(errs...)
+}
+
+func (m *Manager) runStage(ctx context.Context, hooks []hook, stage Stage) []error {
+	logger := logging.GetLogger()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hooks[i].stage != stage {
+			continue
+		}
+		if err := hooks[i].stop(ctx); err != nil {
+			logger.Error("failed to stop component", zap.String("component", hooks[i].name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+			continue
+		}
+		logger.Info("component stopped", zap.String("component", hooks[i].name))
+	}
+	return errs
+}
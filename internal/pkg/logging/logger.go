@@ -8,6 +8,8 @@ import (
 	"strings"
 	"sync"
 
+	"ride-sharing/internal/pkg/tracing"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -165,6 +167,9 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 		zap.String("request_id", getStringFromContext(ctx, RequestIDKey)),
 		zap.String("correlation_id", getStringFromContext(ctx, CorrelationID)),
 	}
+	if traceID, spanID := tracing.IDs(ctx); traceID != "" {
+		fields = append(fields, zap.String("trace_id", traceID), zap.String("span_id", spanID))
+	}
 
 	// Add goroutine ID for debugging concurrent issues
 	var buf [64]byte
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "ride_sharing"
+
+var (
+	OutboxPendingEvents = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_pending_events",
+		Help:      "Number of outbox events not yet published to Kafka.",
+	})
+	OutboxLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_lag_seconds",
+		Help:      "Age of the oldest unpublished outbox event.",
+	})
+	OutboxPublishedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_published_total",
+		Help:      "Outbox events successfully published to Kafka.",
+	})
+	OutboxPublishFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_publish_failures_total",
+		Help:      "Failed attempts to publish outbox events to Kafka.",
+	})
+)
+
+// HTTP
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// Dependencies
+var (
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM statement latency by operation, table and result.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "result"})
+	RedisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Redis command latency by command and result; pipelines are recorded as one command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command", "result"})
+	KafkaProducedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_produced_total",
+		Help:      "Kafka messages produced by topic and result.",
+	}, []string{"topic", "result"})
+	KafkaConsumedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_consumed_total",
+		Help:      "Kafka messages handled by topic, event type and result.",
+	}, []string{"topic", "event_type", "result"})
+	IdentityCacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "identity_cache_lookups_total",
+		Help:      "Identity cache lookups by user type and result (hit, miss or error); hit rate is hit over the total.",
+	}, []string{"user_type", "result"})
+	IdentityCacheLoadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "identity_cache_loads_total",
+		Help:      "Database loads after identity cache misses by user type; lower than misses when concurrent misses are collapsed.",
+	}, []string{"user_type"})
+)
+
+// Notification client
+var (
+	NotificationRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_requests_total",
+		Help:      "Notification gRPC calls by kind and result.",
+	}, []string{"kind", "result"})
+	NotificationRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_retries_total",
+		Help:      "Notification gRPC calls retried after a failure.",
+	}, []string{"kind"})
+	NotificationFallbacksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_fallbacks_total",
+		Help:      "Notifications handed to the Kafka fallback by kind and result.",
+	}, []string{"kind", "result"})
+)
+
+// Business events
+var (
+	RegistrationsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Users registered.",
+	})
+	LoginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by user type, method and result; result=failure counts failed logins.",
+	}, []string{"user_type", "method", "result"})
+	OTPIssuedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_issued_total",
+		Help:      "One time passwords issued by purpose.",
+	}, []string{"purpose"})
+	OTPVerifiedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_verified_total",
+		Help:      "One time password verifications by purpose and result.",
+	}, []string{"purpose", "result"})
+	TripsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "trips_total",
+		Help:      "Trip events seen on the trip topic by state.",
+	}, []string{"state"})
+	FeatureFlagEvaluationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feature_flag_evaluations_total",
+		Help:      "Feature flag evaluations by flag and result (on, off or error).",
+	}, []string{"flag", "result"})
+)
+
+// Result turns an error into the value of a result label
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
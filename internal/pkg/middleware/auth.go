@@ -1,35 +1,69 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
-	"ride-sharing/internal/domains/users/models"
+	auditModels "ride-sharing/internal/domains/audit/models"
+	auditRepository "ride-sharing/internal/domains/audit/repository"
 	"ride-sharing/internal/pkg/auth"
 	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/logging"
 	"ride-sharing/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// APIKeyHeader carries partner API keys as an alternative to bearer tokens
+	APIKeyHeader = "X-API-Key"
+	// ImpersonatedByHeader is set on every response served to an impersonation token
+	ImpersonatedByHeader = "X-Impersonated-By"
 )
 
 type AuthMiddleware struct {
 	tokenService  *auth.TokenService
 	userProviders map[auth.UserType]auth.UserProvider
+	apiKeyAuth    auth.APIKeyAuthenticator
+	auditRepo     auditRepository.AuditRepository
 }
 
 func NewAuthMiddleware(
 	tokenService *auth.TokenService,
 	userProviders map[auth.UserType]auth.UserProvider,
+	apiKeyAuth auth.APIKeyAuthenticator,
+	auditRepo auditRepository.AuditRepository,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		tokenService:  tokenService,
 		userProviders: userProviders,
+		apiKeyAuth:    apiKeyAuth,
+		auditRepo:     auditRepo,
 	}
 }
 
+// Authenticate accepts API keys and full access tokens
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return m.authenticate(false)
+}
+
+// AuthenticatePasswordChange also accepts the restricted token issued for an expired
+// password. It belongs only in front of the change password endpoint.
+func (m *AuthMiddleware) AuthenticatePasswordChange() gin.HandlerFunc {
+	return m.authenticate(true)
+}
+
+func (m *AuthMiddleware) authenticate(allowExpiredPassword bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" && m.apiKeyAuth != nil {
+			m.authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			response.Error(c, errors.NewUnauthorizedError("authorization header required"))
@@ -52,6 +86,12 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		if claims.PasswordExpired && !allowExpiredPassword {
+			response.Error(c, errors.NewForbiddenError("password expired - change it to continue"))
+			c.Abort()
+			return
+		}
+
 		provider, exists := m.userProviders[claims.UserType]
 		if !exists {
 			response.Error(c, errors.NewUnauthorizedError("invalid user type"))
@@ -70,18 +110,24 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		userData, ok := user.(*models.User)
-		if !ok {
+		identity, ok := user.(auth.Identity)
+		if !ok || identity.GetPasswordChangedAt() == nil {
 			response.Error(c, errors.NewInternalError(fmt.Errorf("user is not of expected type")))
 			c.Abort()
 			return
 		}
 
+		if identity.GetSuspendedAt() != nil {
+			response.Error(c, errors.NewForbiddenError("account suspended"))
+			c.Abort()
+			return
+		}
+
 		// Parse claims.PasswordChangedAt (from token) to int64 (Unix timestamp in nanoseconds)
 		tokenPasswordChangedAt := time.Unix(0, claims.PasswordChangedAt)
 
 		// Compare PasswordChangedAt values
-		if tokenPasswordChangedAt.Before(*userData.PasswordChangedAt) {
+		if tokenPasswordChangedAt.Before(*identity.GetPasswordChangedAt()) {
 			response.Error(c, errors.NewUnauthorizedError("password changed - please login again"))
 			c.Abort()
 			return
@@ -90,14 +136,126 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("userType", claims.UserType)
 		c.Set("authUser", user)
+
+		if claims.IsImpersonated() {
+			m.serveImpersonated(c, claims)
+			return
+		}
+		c.Next()
+	}
+}
+
+// serveImpersonated flags the request as impersonated and records it in the audit trail
+func (m *AuthMiddleware) serveImpersonated(c *gin.Context, claims *auth.TokenClaims) {
+	// The acting admin must still exist and not be suspended for the session to remain valid
+	reason, err := auth.CheckActor(c.Request.Context(), m.userProviders, claims.Actor)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.Error(c, appErr)
+		} else {
+			response.Error(c, errors.NewInternalError(err))
+		}
+		c.Abort()
+		return
+	}
+	if reason != "" {
+		response.Error(c, errors.NewUnauthorizedError(reason))
+		c.Abort()
+		return
+	}
+
+	// The audit row is written before the request is served, so an impersonated action is
+	// never carried out without a record of it
+	requestID, _ := c.Request.Context().Value(logging.RequestIDKey).(string)
+	entry := &auditModels.AuditLog{
+		Action:      "impersonated_request",
+		ActorID:     claims.Actor.UserID,
+		ActorType:   string(claims.Actor.UserType),
+		SubjectID:   claims.UserID,
+		SubjectType: string(claims.UserType),
+		Method:      c.Request.Method,
+		Path:        c.Request.URL.Path,
+		IP:          c.ClientIP(),
+		RequestID:   requestID,
+	}
+	if err := m.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		response.Error(c, errors.NewInternalError(fmt.Errorf("failed to record impersonated request: %w", err)))
+		c.Abort()
+		return
+	}
+
+	c.Set("impersonatorID", claims.Actor.UserID)
+	c.Header(ImpersonatedByHeader, claims.Actor.UserID)
+
+	c.Next()
+
+	// Record the outcome even if the client went away mid request
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := m.auditRepo.SetStatus(ctx, entry.ID, c.Writer.Status()); err != nil {
+		logging.GetLogger().WithContext(ctx).Error("failed to record impersonated request status",
+			zap.String("audit_id", entry.ID.String()), zap.Error(err))
+		c.Error(err)
+	}
+}
+
+// authenticateAPIKey resolves the X-API-Key header to a service account identity
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, apiKey string) {
+	identity, err := m.apiKeyAuth.AuthenticateAPIKey(c.Request.Context(), apiKey)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.Error(c, appErr)
+		} else {
+			response.Error(c, errors.NewInternalError(err))
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", identity.ServiceAccountID)
+	c.Set("userType", auth.UserTypeService)
+	c.Set("authUser", identity.Account)
+	c.Set("apiKeyIdentity", identity)
+	c.Next()
+}
+
+// RequireScope restricts API key callers to keys granted the scope. Token based
+// callers are governed by RequireUserType instead and pass through.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("apiKeyIdentity")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		identity, ok := value.(*auth.APIKeyIdentity)
+		if !ok || !identity.HasScope(scope) {
+			response.Error(c, errors.NewForbiddenError("api key missing required scope: "+scope))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyImpersonation blocks sensitive operations (password change, deletion, payments)
+// for requests made with an impersonation token.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonating := c.Get("impersonatorID"); impersonating {
+			response.Error(c, errors.NewForbiddenError("operation not allowed while impersonating"))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
 
-func RequireUserType(userType auth.UserType) gin.HandlerFunc {
+// RequireUserType allows the request through when the caller is any of the given user types
+func RequireUserType(userTypes ...auth.UserType) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		currentType, exists := c.Get("userType")
-		if !exists || currentType != userType {
+		currentType, _ := c.Get("userType")
+		if userType, ok := currentType.(auth.UserType); !ok || !slices.Contains(userTypes, userType) {
 			response.Error(c, errors.NewForbiddenError("access forbidden"))
 			c.Abort()
 			return
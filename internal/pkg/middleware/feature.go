@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	featureFlagService "ride-sharing/internal/domains/featureflags/service"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CityHeader tells feature flags which city a request comes from
+const CityHeader = "X-City"
+
+// RequireFeature answers 404 unless the flag is on for the caller. Placed after
+// Authenticate it evaluates per user; before it, only flags rolled out to 100% pass.
+func RequireFeature(flags *featureFlagService.FeatureFlagService, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := featureFlagService.Subject{City: c.GetHeader(CityHeader)}
+		if userID, ok := c.Get("userID"); ok {
+			subject.UserID, _ = userID.(string)
+		}
+		if userType, ok := c.Get("userType"); ok {
+			if t, ok := userType.(auth.UserType); ok {
+				subject.UserType = string(t)
+			}
+		}
+
+		if !flags.Enabled(c.Request.Context(), key, subject) {
+			response.Error(c, errors.NewNotFoundError("feature not available"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
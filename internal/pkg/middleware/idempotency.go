@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/redis"
+	"ride-sharing/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a mutating request without repeating its effect
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+type IdempotencyConfig struct {
+	// LockTimeout bounds how long a duplicate is told the original is still in flight
+	LockTimeout time.Duration
+	// Retention is how long responses are kept for replay
+	Retention time.Duration
+}
+
+// responseRecorder keeps a copy of the body written by the handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency honours the Idempotency-Key header on POST, PUT, PATCH and DELETE requests.
+// The first request with a key runs and its response is stored; retries with the same
+// body get that response back, retries with a different body are rejected with 422 and
+// retries while the first is still running get 409. Server errors are not stored so the
+// client can retry them. Keys are scoped to the caller and route, so place it after
+// Authenticate on protected routes.
+func Idempotency(store *redis.IdempotencyStore, cfg IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.Error(c, errors.NewValidationError("invalid idempotency key", map[string]string{
+				"idempotency_key": "Must be less than 256 characters",
+			}))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, errors.NewValidationError("failed to read request body", err.Error()))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		logger := logging.GetLogger().WithContext(ctx)
+		scopedKey := idempotencyScope(c) + ":" + key
+		fingerprint := requestFingerprint(c, body)
+
+		outcome, stored, lock, err := store.Begin(ctx, scopedKey, fingerprint, cfg.LockTimeout)
+		if err != nil {
+			// Serving the request beats failing it while Redis is unavailable
+			logger.Warn("idempotency check failed, processing without it", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		switch outcome {
+		case redis.IdempotencyReplay:
+			for name, value := range stored.Headers {
+				c.Header(name, value)
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.Status, stored.Headers["Content-Type"], stored.Body)
+			c.Abort()
+			return
+		case redis.IdempotencyMismatch:
+			response.Error(c, errors.NewUnprocessableError("idempotency key was already used for a different request"))
+			c.Abort()
+			return
+		case redis.IdempotencyInFlight:
+			response.Error(c, errors.NewConflictError("a request with this idempotency key is still being processed"))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			// Store even if the client went away, that is exactly when it will retry
+			storeCtx := context.WithoutCancel(ctx)
+			status := recorder.Status()
+			// Nothing written means the handler panicked; leave that to a retry as well
+			if !recorder.Written() || status >= http.StatusInternalServerError {
+				if err := store.Release(storeCtx, lock); err != nil {
+					logger.Warn("failed to release idempotency lock", zap.Error(err))
+				}
+				return
+			}
+
+			err := store.Complete(storeCtx, scopedKey, lock, redis.StoredResponse{
+				Fingerprint: fingerprint,
+				Status:      status,
+				Headers:     replayHeaders(recorder.Header()),
+				Body:        recorder.body.Bytes(),
+			}, cfg.Retention)
+			if err != nil {
+				logger.Warn("failed to store idempotent response", zap.Error(err))
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyScope keeps callers from colliding with, or replaying, each other's keys
+func idempotencyScope(c *gin.Context) string {
+	caller := "anonymous"
+	if userID, ok := c.Get("userID"); ok {
+		if id, ok := userID.(string); ok && id != "" {
+			caller = id
+		}
+	}
+	return caller + ":" + c.Request.Method + ":" + c.FullPath()
+}
+
+// requestFingerprint identifies the request a key was first used for
+func requestFingerprint(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayHeaders keeps the headers that describe the response itself. Per request
+// headers such as request IDs are set again by the middleware on the retry.
+func replayHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for _, name := range []string{"Content-Type", "Location"} {
+		if value := header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
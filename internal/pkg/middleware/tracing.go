@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled constantly by probes and scrapers and would only add noise
+var untracedPaths = map[string]bool{
+	"/metrics":             true,
+	"/health":              true,
+	"/ready":               true,
+	"/health/dependencies": true,
+}
+
+// TracingMiddleware starts a server span per request, continuing the caller's trace when
+// a W3C traceparent header is present. It must run before LoggingMiddleware so request
+// logs carry the trace ID.
+func TracingMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName,
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return !untracedPaths[r.URL.Path]
+		}),
+	)
+}
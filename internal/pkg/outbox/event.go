@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ride-sharing/internal/pkg/tracing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Event is a message waiting in the outbox table to be relayed to Kafka. The auto
+// incrementing ID gives the publish order; AggregateKey is used as the Kafka key so
+// all events of one aggregate land on the same partition in order.
+type Event struct {
+	ID            uint64            `gorm:"primaryKey;autoIncrement"`
+	EventID       uuid.UUID         `gorm:"type:uuid;uniqueIndex;not null"`
+	Topic         string            `gorm:"not null"`
+	AggregateKey  string            `gorm:"not null;index"`
+	EventType     string            `gorm:"not null"`
+	Payload       string            `gorm:"type:jsonb;not null"`
+	Headers       map[string]string `gorm:"serializer:json;type:jsonb"`
+	Attempts      int               `gorm:"not null;default:0"`
+	LastError     string
+	NextAttemptAt time.Time  `gorm:"not null;index"`
+	PublishedAt   *time.Time `gorm:"index"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// Message is what domain code hands to Enqueue
+type Message struct {
+	EventID   uuid.UUID // optional, generated when empty
+	Topic     string
+	Key       string
+	EventType string
+	Payload   interface{} // marshalled to JSON unless already []byte or json.RawMessage
+	Headers   map[string]string
+}
+
+// Enqueue stores messages in the outbox through tx, so they commit or roll back
+// together with the domain changes made in the same transaction.
+func Enqueue(tx *gorm.DB, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	ctx := tx.Statement.Context
+	events := make([]Event, 0, len(msgs))
+	for _, msg := range msgs {
+		payload, err := marshalPayload(msg.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox payload for %s: %w", msg.EventType, err)
+		}
+
+		eventID := msg.EventID
+		if eventID == uuid.Nil {
+			eventID = uuid.New()
+		}
+
+		// Record the current trace so the relay can continue it when publishing
+		headers := make(map[string]string, len(msg.Headers)+1)
+		for key, value := range msg.Headers {
+			headers[key] = value
+		}
+		if ctx != nil {
+			tracing.Inject(ctx, headers)
+		}
+
+		events = append(events, Event{
+			EventID:       eventID,
+			Topic:         msg.Topic,
+			AggregateKey:  msg.Key,
+			EventType:     msg.EventType,
+			Payload:       payload,
+			Headers:       headers,
+			NextAttemptAt: now,
+		})
+	}
+
+	return tx.Create(&events).Error
+}
+
+func marshalPayload(payload interface{}) (string, error) {
+	switch v := payload.(type) {
+	case json.RawMessage:
+		return string(v), nil
+	case []byte:
+		return string(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
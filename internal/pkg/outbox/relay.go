@@ -0,0 +1,197 @@
+package outbox
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"ride-sharing/internal/pkg/kafka"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// relayLockID is the Postgres advisory lock key that elects a single active relay
+const relayLockID = 7_320_021
+
+// pendingQuery selects due events in publish order, skipping any event whose aggregate
+// still has an earlier event waiting for a retry, so per-key ordering is preserved.
+const pendingQuery = `
+SELECT * FROM outbox_events o
+WHERE o.published_at IS NULL
+  AND o.next_attempt_at <= ?
+  AND NOT EXISTS (
+    SELECT 1 FROM outbox_events p
+    WHERE p.aggregate_key = o.aggregate_key
+      AND p.published_at IS NULL
+      AND p.id < o.id
+      AND p.next_attempt_at > ?
+  )
+ORDER BY o.id
+LIMIT ?`
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxBackoff   time.Duration
+	Retention    time.Duration // published events older than this are deleted
+}
+
+// Relay publishes outbox events to Kafka with at-least-once delivery. Events are marked
+// published only after the broker acknowledges them, so consumers must dedupe on event ID.
+type Relay struct {
+	db       *gorm.DB
+	producer *kafka.Producer
+	cfg      RelayConfig
+}
+
+func NewRelay(db *gorm.DB, producer *kafka.Producer, cfg RelayConfig) *Relay {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	return &Relay{db: db, producer: producer, cfg: cfg}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	logger := logging.GetLogger()
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.relayBatch(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("outbox relay failed", zap.Error(err))
+			}
+			r.recordLag(ctx)
+		case <-cleanup.C:
+			if err := r.deletePublished(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("outbox cleanup failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	// The lock is session scoped and taken on its own connection, so no transaction stays
+	// open while waiting for Kafka
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Only one replica relays at a time, otherwise per-key ordering could not be guaranteed
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", relayLockID).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", relayLockID)
+
+	now := time.Now()
+	var events []Event
+	if err := r.db.WithContext(ctx).Raw(pendingQuery, now, now, r.cfg.BatchSize).Scan(&events).Error; err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	// One write for the whole batch. The outbox producer sizes its batches so a partition's
+	// messages normally go out in one request and an aggregate's events succeed or fail together.
+	msgs := make([]kafka.Message, len(events))
+	for i, event := range events {
+		msgs[i] = kafka.Message{
+			Topic:   event.Topic,
+			Key:     event.AggregateKey,
+			Value:   []byte(event.Payload),
+			Headers: event.Headers,
+		}
+	}
+	errs := r.producer.PublishBatch(ctx, msgs)
+
+	// Record the outcome even when shutting down, acknowledged events must not be sent again
+	recordCtx := context.WithoutCancel(ctx)
+	return r.db.WithContext(recordCtx).Transaction(func(tx *gorm.DB) error {
+		recordedAt := time.Now()
+		for i := range events {
+			event := &events[i]
+			if errs[i] != nil {
+				// pendingQuery holds back later events of the aggregate until this one goes through
+				metrics.OutboxPublishFailuresTotal.Inc()
+				if err := tx.Model(event).Updates(map[string]interface{}{
+					"attempts":        event.Attempts + 1,
+					"last_error":      errs[i].Error(),
+					"next_attempt_at": recordedAt.Add(r.backoff(event.Attempts + 1)),
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			metrics.OutboxPublishedTotal.Inc()
+			if err := tx.Model(event).Update("published_at", recordedAt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Relay) recordLag(ctx context.Context) {
+	var stats struct {
+		Pending int64
+		Oldest  *time.Time
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&Event{}).
+		Select("COUNT(*) AS pending, MIN(created_at) AS oldest").
+		Where("published_at IS NULL").
+		Scan(&stats).Error; err != nil {
+		return
+	}
+
+	metrics.OutboxPendingEvents.Set(float64(stats.Pending))
+	if stats.Oldest == nil {
+		metrics.OutboxLagSeconds.Set(0)
+		return
+	}
+	metrics.OutboxLagSeconds.Set(time.Since(*stats.Oldest).Seconds())
+}
+
+func (r *Relay) deletePublished(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", time.Now().Add(-r.cfg.Retention)).
+		Delete(&Event{}).Error
+}
+
+func (r *Relay) backoff(attempt int) time.Duration {
+	delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+	if delay > r.cfg.MaxBackoff || delay <= 0 {
+		return r.cfg.MaxBackoff
+	}
+	return delay
+}
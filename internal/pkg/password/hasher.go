@@ -0,0 +1,48 @@
+package password
+
+import "fmt"
+
+// Hasher hashes and verifies passwords for a single algorithm
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	// Supports reports whether the encoded hash was produced by this algorithm
+	Supports(encoded string) bool
+	// NeedsRehash reports whether the encoded hash uses outdated parameters
+	NeedsRehash(encoded string) bool
+}
+
+// Manager hashes new passwords with the preferred algorithm while still
+// verifying hashes produced by any of the legacy ones.
+type Manager struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+func NewManager(preferred Hasher, legacy ...Hasher) *Manager {
+	return &Manager{
+		preferred: preferred,
+		hashers:   append([]Hasher{preferred}, legacy...),
+	}
+}
+
+func (m *Manager) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
+
+func (m *Manager) Verify(password string, encoded string) (bool, error) {
+	for _, hasher := range m.hashers {
+		if hasher.Supports(encoded) {
+			return hasher.Verify(password, encoded)
+		}
+	}
+	return false, fmt.Errorf("unsupported password hash format")
+}
+
+// NeedsRehash reports whether the hash should be replaced with one from the preferred algorithm
+func (m *Manager) NeedsRehash(encoded string) bool {
+	if !m.preferred.Supports(encoded) {
+		return true
+	}
+	return m.preferred.NeedsRehash(encoded)
+}
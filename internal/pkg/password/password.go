@@ -1,29 +1,26 @@
 package password
 
 import (
-	"errors"
-
 	"golang.org/x/crypto/bcrypt"
 )
 
+// defaultManager backs the package level helpers, replaced at startup with the configured one
+var defaultManager = NewManager(NewBcryptHasher(bcrypt.DefaultCost))
+
+// SetDefault replaces the manager used by HashPassword, CheckPassword and NeedsRehash
+func SetDefault(manager *Manager) {
+	defaultManager = manager
+}
+
 func HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedBytes), nil
+	return defaultManager.Hash(password)
 }
 
 func CheckPassword(inputPassword string, hashedPassword string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(inputPassword))
-	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			// Password doesn't match
-			return false, nil
-		}
-		// Some other error occurred
-		return false, err
-	}
-	// Password matches
-	return true, nil
+	return defaultManager.Verify(inputPassword, hashedPassword)
+}
+
+// NeedsRehash reports whether a stored hash uses an outdated algorithm or parameters
+func NeedsRehash(hashedPassword string) bool {
+	return defaultManager.NeedsRehash(hashedPassword)
 }
@@ -0,0 +1,165 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	upperPattern   = regexp.MustCompile(`[A-Z]`)
+	lowerPattern   = regexp.MustCompile(`[a-z]`)
+	digitPattern   = regexp.MustCompile(`[0-9]`)
+	specialPattern = regexp.MustCompile(`[^a-zA-Z0-9]`)
+)
+
+// PolicyConfig holds the tunable password rules
+type PolicyConfig struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+	MaxAge         time.Duration // zero disables expiry
+	HistorySize    int           // number of previous passwords that cannot be reused, zero disables
+	BreachListFile string        // newline separated list of known breached passwords
+}
+
+// Policy validates passwords against the configured rules and the breached password list
+type Policy struct {
+	cfg      PolicyConfig
+	breached map[string]struct{}
+}
+
+// Rules is the client facing description of the policy, used for UI hints
+type Rules struct {
+	MinLength      int    `json:"min_length"`
+	RequireUpper   bool   `json:"require_upper"`
+	RequireLower   bool   `json:"require_lower"`
+	RequireDigit   bool   `json:"require_digit"`
+	RequireSpecial bool   `json:"require_special"`
+	MaxAgeDays     int    `json:"max_age_days"`
+	HistorySize    int    `json:"history_size"`
+	BreachCheck    bool   `json:"breach_check"`
+	Description    string `json:"description"`
+}
+
+// DefaultPolicy mirrors the rules that used to be hardcoded in the strongpassword validator
+func DefaultPolicy() *Policy {
+	return &Policy{
+		cfg: PolicyConfig{
+			MinLength:      8,
+			RequireUpper:   true,
+			RequireLower:   true,
+			RequireDigit:   true,
+			RequireSpecial: true,
+		},
+		breached: map[string]struct{}{},
+	}
+}
+
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	policy := &Policy{cfg: cfg, breached: map[string]struct{}{}}
+	if cfg.BreachListFile == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(cfg.BreachListFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.breached[line] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return policy, nil
+}
+
+// MeetsRules reports whether the password satisfies the length and character class rules
+func (p *Policy) MeetsRules(password string) bool {
+	if len(password) < p.cfg.MinLength {
+		return false
+	}
+	if p.cfg.RequireUpper && !upperPattern.MatchString(password) {
+		return false
+	}
+	if p.cfg.RequireLower && !lowerPattern.MatchString(password) {
+		return false
+	}
+	if p.cfg.RequireDigit && !digitPattern.MatchString(password) {
+		return false
+	}
+	if p.cfg.RequireSpecial && !specialPattern.MatchString(password) {
+		return false
+	}
+	return true
+}
+
+// IsBreached reports whether the password appears in the breached password list. The
+// comparison is exact, as breach corpora list the case variants that were actually leaked.
+func (p *Policy) IsBreached(password string) bool {
+	_, found := p.breached[password]
+	return found
+}
+
+// IsExpired reports whether a password last changed at changedAt has exceeded the maximum age
+func (p *Policy) IsExpired(changedAt *time.Time) bool {
+	if p.cfg.MaxAge <= 0 || changedAt == nil {
+		return false
+	}
+	return time.Since(*changedAt) > p.cfg.MaxAge
+}
+
+func (p *Policy) HistorySize() int {
+	return p.cfg.HistorySize
+}
+
+// Description returns a human readable summary of the rules for API docs/errors
+func (p *Policy) Description() string {
+	var classes []string
+	if p.cfg.RequireUpper {
+		classes = append(classes, "1 uppercase")
+	}
+	if p.cfg.RequireLower {
+		classes = append(classes, "1 lowercase")
+	}
+	if p.cfg.RequireDigit {
+		classes = append(classes, "1 digit")
+	}
+	if p.cfg.RequireSpecial {
+		classes = append(classes, "1 special character")
+	}
+
+	description := fmt.Sprintf("Password must contain at least %d characters", p.cfg.MinLength)
+	if len(classes) > 0 {
+		description += " including: " + strings.Join(classes, ", ")
+	}
+	return description
+}
+
+func (p *Policy) Rules() Rules {
+	return Rules{
+		MinLength:      p.cfg.MinLength,
+		RequireUpper:   p.cfg.RequireUpper,
+		RequireLower:   p.cfg.RequireLower,
+		RequireDigit:   p.cfg.RequireDigit,
+		RequireSpecial: p.cfg.RequireSpecial,
+		MaxAgeDays:     int(p.cfg.MaxAge / (24 * time.Hour)),
+		HistorySize:    p.cfg.HistorySize,
+		BreachCheck:    len(p.breached) > 0,
+		Description:    p.Description(),
+	}
+}
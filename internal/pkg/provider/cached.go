@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+	"ride-sharing/internal/pkg/redis"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// IdentityCache keeps compact identity snapshots in Redis so token validation does not
+// query Postgres on every request. Writers call Invalidate after committing a change to an
+// account. Entries are versioned, so a snapshot loaded before the change is never stored
+// after it.
+type IdentityCache struct {
+	cache *redis.VersionedCache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+func NewIdentityCache(cache *redis.VersionedCache, ttl time.Duration) *IdentityCache {
+	return &IdentityCache{cache: cache, ttl: ttl}
+}
+
+// Wrap returns a provider that reads through the cache before falling back to next.
+// It returns *auth.IdentitySnapshot rather than the full account, so it only suits
+// callers that need auth.Identity.
+func (c *IdentityCache) Wrap(next auth.UserProvider) auth.UserProvider {
+	return &cachedProvider{next: next, identities: c}
+}
+
+// Invalidate drops the cached snapshot of an account. Failures are logged, the entry then expires with its ttl.
+func (c *IdentityCache) Invalidate(ctx context.Context, userType auth.UserType, id string) {
+	if err := c.Evict(ctx, userType, id); err != nil {
+		logging.GetLogger().WithContext(ctx).Warn("failed to invalidate cached identity",
+			zap.String("user_type", string(userType)), zap.String("user_id", id), zap.Error(err))
+	}
+}
+
+// Evict is Invalidate for callers that need to report the failure themselves
+func (c *IdentityCache) Evict(ctx context.Context, userType auth.UserType, id string) error {
+	return c.cache.Invalidate(ctx, identityKey(userType, id))
+}
+
+type cachedProvider struct {
+	next       auth.UserProvider
+	identities *IdentityCache
+}
+
+func (p *cachedProvider) GetByID(ctx context.Context, id string, userType auth.UserType) (interface{}, error) {
+	c := p.identities
+	key := identityKey(userType, id)
+
+	var snapshot auth.IdentitySnapshot
+	hit, generation, err := c.cache.Get(ctx, key, &snapshot)
+	cacheable := err == nil
+	switch {
+	case err != nil:
+		// A Redis outage falls back to Postgres
+		metrics.IdentityCacheLookupsTotal.WithLabelValues(string(userType), "error").Inc()
+		logging.GetLogger().WithContext(ctx).Warn("failed to read cached identity", zap.String("user_type", string(userType)), zap.Error(err))
+	case hit:
+		metrics.IdentityCacheLookupsTotal.WithLabelValues(string(userType), "hit").Inc()
+		return &snapshot, nil
+	default:
+		metrics.IdentityCacheLookupsTotal.WithLabelValues(string(userType), "miss").Inc()
+	}
+
+	// Concurrent misses for the same account share one load. Callers that arrive after an
+	// invalidation see a new generation and do not join a load that started before it. The
+	// load must not be cancelled by whichever caller happened to start it.
+	flight := key + "@" + strconv.FormatInt(generation, 10)
+	if !cacheable {
+		flight = key + "@uncached"
+	}
+	loadCtx := context.WithoutCancel(ctx)
+	account, err, _ := c.group.Do(flight, func() (interface{}, error) {
+		metrics.IdentityCacheLoadsTotal.WithLabelValues(string(userType)).Inc()
+		account, err := p.next.GetByID(loadCtx, id, userType)
+		if err != nil {
+			return nil, err
+		}
+
+		identity, ok := account.(auth.Identity)
+		if !ok || identity.GetPasswordChangedAt() == nil {
+			// Not cacheable, let the caller deal with it
+			return account, nil
+		}
+
+		snapshot := &auth.IdentitySnapshot{
+			ID:                id,
+			UserType:          userType,
+			PasswordChangedAt: identity.GetPasswordChangedAt(),
+			SuspendedAt:       identity.GetSuspendedAt(),
+		}
+		if !cacheable {
+			return snapshot, nil
+		}
+		// Not stored when the account changed while it was loading; the next lookup reloads it
+		if _, err := c.cache.Set(loadCtx, key, generation, snapshot, c.ttl); err != nil {
+			logging.GetLogger().WithContext(loadCtx).Warn("failed to cache identity", zap.String("user_type", string(userType)), zap.Error(err))
+		}
+		return snapshot, nil
+	})
+	return account, err
+}
+
+func identityKey(userType auth.UserType, id string) string {
+	return string(userType) + ":" + id
+}
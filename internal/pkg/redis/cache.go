@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores JSON encoded values under a key prefix
+type Cache struct {
+	cli    *redis.Client
+	prefix string
+}
+
+func NewCache(client *Client, prefix string) *Cache {
+	return &Cache{cli: client.cli, prefix: prefix}
+}
+
+// Get decodes the cached value into dst and reports whether the key was present
+func (c *Cache) Get(ctx context.Context, key string, dst interface{}) (bool, error) {
+	data, err := c.cli.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.cli.Set(ctx, c.prefix+key, data, ttl).Err()
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.cli.Del(ctx, prefixed...).Err()
+}
@@ -14,13 +14,14 @@ type Client struct {
 }
 
 func New(cfg *config.Config) *Client {
-	return &Client{
-		cli: redis.NewClient(&redis.Options{
-			Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
-			Password: cfg.Redis.Password,
-			DB:       cfg.Redis.DB,
-		}),
-	}
+	cli := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	cli.AddHook(metricsHook{})
+	cli.AddHook(tracingHook{})
+	return &Client{cli: cli}
 }
 
 func (c *Client) Ping(ctx context.Context) error {
@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Outcomes of IdempotencyStore.Begin
+const (
+	// IdempotencyAcquired means the caller holds the lock and must run the request
+	IdempotencyAcquired = iota
+	// IdempotencyReplay means a stored response for the same request exists
+	IdempotencyReplay
+	// IdempotencyInFlight means the same request is being processed right now
+	IdempotencyInFlight
+	// IdempotencyMismatch means the key was already used for a different request
+	IdempotencyMismatch
+)
+
+// releaseLockScript deletes the lock only if it still holds our token, so a request that
+// outlived its lock cannot release a lock taken by a later retry
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// StoredResponse is a completed response kept for replay
+type StoredResponse struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status"`
+	Headers     map[string]string `json:"headers"`
+	Body        []byte            `json:"body"`
+}
+
+// IdempotencyLock is held while a request runs; pass it back to Complete or Release
+type IdempotencyLock struct {
+	key   string
+	value string
+}
+
+// IdempotencyStore keeps one lock and one stored response per idempotency key. The lock
+// holds the request fingerprint so concurrent requests can tell a duplicate from misuse.
+type IdempotencyStore struct {
+	cli *redis.Client
+}
+
+func NewIdempotencyStore(client *Client) *IdempotencyStore {
+	return &IdempotencyStore{cli: client.cli}
+}
+
+// Begin looks up key. With IdempotencyAcquired the returned lock must be completed or
+// released; with IdempotencyReplay the stored response is returned.
+func (s *IdempotencyStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (int, *StoredResponse, *IdempotencyLock, error) {
+	stored, err := s.stored(ctx, key)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if stored != nil {
+		return compare(stored, fingerprint), stored, nil, nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	lock := &IdempotencyLock{key: lockKey(key), value: fingerprint + ":" + token}
+	acquired, err := s.cli.SetNX(ctx, lock.key, lock.value, lockTTL).Result()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if !acquired {
+		holder, err := s.cli.Get(ctx, lock.key).Result()
+		if errors.Is(err, redis.Nil) {
+			// Released in the meantime; the response is stored unless that request failed
+			return s.Begin(ctx, key, fingerprint, lockTTL)
+		}
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if !strings.HasPrefix(holder, fingerprint+":") {
+			return IdempotencyMismatch, nil, nil, nil
+		}
+		return IdempotencyInFlight, nil, nil, nil
+	}
+
+	// The previous holder may have stored its response just before we took the lock
+	stored, err = s.stored(ctx, key)
+	if err != nil {
+		s.Release(ctx, lock)
+		return 0, nil, nil, err
+	}
+	if stored != nil {
+		s.Release(ctx, lock)
+		return compare(stored, fingerprint), stored, nil, nil
+	}
+	return IdempotencyAcquired, nil, lock, nil
+}
+
+// Complete stores the response for ttl and releases the lock
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, lock *IdempotencyLock, response StoredResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	if err := s.cli.Set(ctx, responseKey(key), data, ttl).Err(); err != nil {
+		return err
+	}
+	return s.Release(ctx, lock)
+}
+
+// Release drops the lock without storing a response, so the request can be retried
+func (s *IdempotencyStore) Release(ctx context.Context, lock *IdempotencyLock) error {
+	return releaseLockScript.Run(ctx, s.cli, []string{lock.key}, lock.value).Err()
+}
+
+func (s *IdempotencyStore) stored(ctx context.Context, key string) (*StoredResponse, error) {
+	data, err := s.cli.Get(ctx, responseKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response StoredResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func compare(stored *StoredResponse, fingerprint string) int {
+	if stored.Fingerprint != fingerprint {
+		return IdempotencyMismatch
+	}
+	return IdempotencyReplay
+}
+
+func lockKey(key string) string {
+	return "idempotency:lock:" + key
+}
+
+func responseKey(key string) string {
+	return "idempotency:response:" + key
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ride-sharing/internal/pkg/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// metricsHook times commands into redis_command_duration_seconds. A missing key (redis.Nil)
+// is a normal answer, not a failure.
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", start, err)
+		return err
+	}
+}
+
+func observeCommand(command string, start time.Time, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	metrics.RedisCommandDuration.WithLabelValues(command, metrics.Result(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PubSub fans messages out to subscribers on every instance, e.g. for real-time delivery
+type PubSub struct {
+	cli    *redis.Client
+	closed chan struct{}
+	once   sync.Once
+}
+
+func NewPubSub(client *Client) *PubSub {
+	return &PubSub{cli: client.cli, closed: make(chan struct{})}
+}
+
+// Close ends every active subscription, e.g. so long-lived streams finish before shutdown
+func (p *PubSub) Close() {
+	p.once.Do(func() { close(p.closed) })
+}
+
+func (p *PubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	return p.cli.Publish(ctx, channel, payload).Err()
+}
+
+// Subscribe streams payloads published on channel until ctx is done
+func (p *PubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	select {
+	case <-p.closed:
+		return nil, errors.New("pubsub is closed")
+	default:
+	}
+
+	sub := p.cli.Subscribe(ctx, channel)
+	// Wait for the subscription to be confirmed so no message published right after is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	out := make(chan []byte)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.closed:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
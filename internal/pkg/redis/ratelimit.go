@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RateLimiter struct {
+	cli *redis.Client
+}
+
+func NewRateLimiter(client *Client) *RateLimiter {
+	return &RateLimiter{cli: client.cli}
+}
+
+// Allow records a hit in the current fixed window and reports whether key is still within limit
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	windowKey := fmt.Sprintf("ratelimit:%s:%d", key, time.Now().UnixNano()/int64(window))
+
+	pipe := r.cli.TxPipeline()
+	count := pipe.Incr(ctx, windowKey)
+	pipe.Expire(ctx, windowKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return count.Val() <= int64(limit), nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"ride-sharing/internal/pkg/errors"
+	"ride-sharing/internal/pkg/metrics"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,10 +13,28 @@ import (
 
 type OTPStore struct {
 	cli *redis.Client
+	cfg OTPConfig
 }
 
-func NewOTPStore(client *Client) *OTPStore {
-	return &OTPStore{cli: client.cli}
+type OTPConfig struct {
+	TTL time.Duration
+	// BoundTTL applies to codes bound to a device, see SetBoundOTP
+	BoundTTL time.Duration
+	// MaxAttempts is the number of wrong bound codes accepted before the code is discarded
+	MaxAttempts int
+}
+
+func NewOTPStore(client *Client, cfg OTPConfig) *OTPStore {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 2 * time.Minute
+	}
+	if cfg.BoundTTL <= 0 {
+		cfg.BoundTTL = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	return &OTPStore{cli: client.cli, cfg: cfg}
 }
 
 func (s *OTPStore) SetOTP(ctx context.Context, email, otp string, otpType string) error {
@@ -29,8 +49,27 @@ func (s *OTPStore) SetOTP(ctx context.Context, email, otp string, otpType string
 		return errors.NewConflictError("OTP already exists for this email")
 	}
 
-	// Set the new OTP with 2-minute expiration
-	return s.cli.Set(ctx, key, otp, 2*time.Minute).Err()
+	// Set the new OTP with the configured expiration
+	if err := s.cli.Set(ctx, key, otp, s.cfg.TTL).Err(); err != nil {
+		return err
+	}
+	metrics.OTPIssuedTotal.WithLabelValues(otpType).Inc()
+	return nil
+}
+
+// CheckOTP reports whether otp matches the stored code without consuming it. Callers that
+// still have to validate the rest of the request use it first and consume the code with
+// VerifyAndDeleteOTP once the request is acceptable.
+func (s *OTPStore) CheckOTP(ctx context.Context, email, otp string, otpType string) (bool, error) {
+	key := fmt.Sprintf("otp:%s:%s", otpType, email)
+	storedOTP, err := s.cli.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return storedOTP == otp, nil
 }
 
 func (s *OTPStore) VerifyAndDeleteOTP(ctx context.Context, email, otp string, otpType string) (bool, error) {
@@ -56,10 +95,95 @@ func (s *OTPStore) VerifyAndDeleteOTP(ctx context.Context, email, otp string, ot
 
 	err := s.cli.Watch(ctx, txFn, key)
 	if err == redis.Nil {
+		metrics.OTPVerifiedTotal.WithLabelValues(otpType, "invalid").Inc()
 		return false, nil // OTP mismatch or expired
 	}
 	if err != nil {
 		return false, err
 	}
+	metrics.OTPVerifiedTotal.WithLabelValues(otpType, "valid").Inc()
 	return true, nil
 }
+
+// SetBoundOTP stores an OTP that can only be redeemed from the device it was issued to.
+func (s *OTPStore) SetBoundOTP(ctx context.Context, identifier, otp, otpType, deviceID string) error {
+	key := fmt.Sprintf("otp:%s:%s", otpType, identifier)
+
+	exists, err := s.cli.Exists(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 1 {
+		return errors.NewConflictError("OTP already exists for this account")
+	}
+
+	// Keep the code, the device it is bound to and the failed attempt count together
+	pipe := s.cli.TxPipeline()
+	pipe.HSet(ctx, key, "otp", otp, "device", deviceID, "attempts", 0)
+	pipe.Expire(ctx, key, s.cfg.BoundTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	metrics.OTPIssuedTotal.WithLabelValues(otpType).Inc()
+	return nil
+}
+
+// VerifyBoundOTP checks the OTP and its device binding. Failed attempts are counted and
+// the OTP is discarded once MaxAttempts is reached, so a new one has to be requested.
+func (s *OTPStore) VerifyBoundOTP(ctx context.Context, identifier, otp, otpType, deviceID string) (bool, error) {
+	key := fmt.Sprintf("otp:%s:%s", otpType, identifier)
+	maxAttempts := s.cfg.MaxAttempts
+
+	valid := false
+	txFn := func(tx *redis.Tx) error {
+		values, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return redis.Nil
+		}
+
+		if values["otp"] == otp && values["device"] == deviceID {
+			valid = true
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Del(ctx, key)
+				return nil
+			})
+			return err
+		}
+
+		attempts, _ := strconv.Atoi(values["attempts"])
+		attempts++
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if attempts >= maxAttempts {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.HIncrBy(ctx, key, "attempts", 1)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if attempts >= maxAttempts {
+			return errors.NewTooManyRequestsError("too many invalid attempts - request a new code")
+		}
+		return nil
+	}
+
+	err := s.cli.Watch(ctx, txFn, key)
+	if err == redis.Nil {
+		metrics.OTPVerifiedTotal.WithLabelValues(otpType, "invalid").Inc()
+		return false, nil // OTP expired or never issued
+	}
+	if err != nil {
+		return false, err
+	}
+	result := "invalid"
+	if valid {
+		result = "valid"
+	}
+	metrics.OTPVerifiedTotal.WithLabelValues(otpType, result).Inc()
+	return valid, nil
+}
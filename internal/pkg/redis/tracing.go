@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	"ride-sharing/internal/pkg/tracing"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingHook wraps every command and pipeline in a client span. As with the metrics
+// hook, redis.Nil is not recorded as an error.
+type tracingHook struct{}
+
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startCommandSpan(ctx, cmd.Name())
+		err := next(ctx, cmd)
+		endCommandSpan(span, err)
+		return err
+	}
+}
+
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startCommandSpan(ctx, "pipeline")
+		span.SetAttributes(attribute.Int("db.redis.pipeline_length", len(cmds)))
+		err := next(ctx, cmds)
+		endCommandSpan(span, err)
+		return err
+	}
+}
+
+func startCommandSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "redis."+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", command),
+		),
+	)
+}
+
+func endCommandSpan(span trace.Span, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// generationTTL keeps a generation around well past any load that could have read it, so
+// the counter cannot expire and restart while a stale write is still pending
+const generationTTL = 24 * time.Hour
+
+// setIfGenerationScript stores the value only while the generation still matches the one
+// read before loading it
+var setIfGenerationScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[2])
+if (current or "0") ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+// invalidateScript bumps the generation and drops the value in one step
+var invalidateScript = redis.NewScript(`
+redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[1])
+return redis.call("DEL", KEYS[1])
+`)
+
+// VersionedCache is a JSON cache whose entries carry a generation. Invalidate bumps the
+// generation, so a value loaded before an invalidation can no longer be stored after it.
+type VersionedCache struct {
+	cli    *redis.Client
+	prefix string
+}
+
+func NewVersionedCache(client *Client, prefix string) *VersionedCache {
+	return &VersionedCache{cli: client.cli, prefix: prefix}
+}
+
+// Get decodes the cached value into dst and reports whether it was present. The returned
+// generation must be passed to Set when the value is loaded after a miss.
+func (c *VersionedCache) Get(ctx context.Context, key string, dst interface{}) (bool, int64, error) {
+	values, err := c.cli.MGet(ctx, c.valueKey(key), c.generationKey(key)).Result()
+	if err != nil {
+		return false, 0, err
+	}
+
+	var generation int64
+	if raw, ok := values[1].(string); ok {
+		if generation, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return false, 0, err
+		}
+	}
+
+	raw, ok := values[0].(string)
+	if !ok {
+		return false, generation, nil
+	}
+	if err := json.Unmarshal([]byte(raw), dst); err != nil {
+		return false, generation, err
+	}
+	return true, generation, nil
+}
+
+// Set stores value unless the key was invalidated since generation was read, and reports
+// whether it was stored
+func (c *VersionedCache) Set(ctx context.Context, key string, generation int64, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	stored, err := setIfGenerationScript.Run(ctx, c.cli,
+		[]string{c.valueKey(key), c.generationKey(key)},
+		strconv.FormatInt(generation, 10), data, ttl.Milliseconds(),
+	).Int()
+	return stored == 1, err
+}
+
+// Invalidate drops the value and bumps its generation. Call it after the change it
+// reflects has been committed.
+func (c *VersionedCache) Invalidate(ctx context.Context, key string) error {
+	return invalidateScript.Run(ctx, c.cli,
+		[]string{c.valueKey(key), c.generationKey(key)},
+		generationTTL.Milliseconds(),
+	).Err()
+}
+
+func (c *VersionedCache) valueKey(key string) string {
+	return c.prefix + key
+}
+
+func (c *VersionedCache) generationKey(key string) string {
+	return c.prefix + "generation:" + key
+}
@@ -0,0 +1,213 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Names of the templates shipped with the service
+const (
+	RegisterOTP       = "register_otp"
+	ForgetPasswordOTP = "forget_password_otp"
+	LoginCode         = "login_code"
+)
+
+var ErrTemplateNotFound = errors.New("notification template not found")
+
+//go:embed files
+var embedded embed.FS
+
+// Rendered is the output of a template for one locale. HTML is empty when the template
+// has no html block, e.g. SMS only templates.
+type Rendered struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// Info describes the versions and locales available for a template
+type Info struct {
+	Name     string   `json:"name"`
+	Versions []int    `json:"versions"`
+	Locales  []string `json:"locales"` // locales of the latest version
+}
+
+type variant struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer holds every template variant, laid out as <name>/v<version>/<locale>.tmpl.
+// Each file defines a "subject", a "text" and optionally an "html" block.
+type Renderer struct {
+	defaultLocale string
+	// name -> version -> locale
+	variants map[string]map[int]map[string]*variant
+}
+
+// NewRenderer loads the embedded templates, or the ones under dir when it is set so copy
+// can be changed without a rebuild.
+func NewRenderer(defaultLocale, dir string) (*Renderer, error) {
+	var source fs.FS = embedded
+	root := "files"
+	if dir != "" {
+		source, root = os.DirFS(dir), "."
+	}
+
+	r := &Renderer{defaultLocale: defaultLocale, variants: map[string]map[int]map[string]*variant{}}
+	err := fs.WalkDir(source, root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(file) != ".tmpl" {
+			return err
+		}
+
+		parts := strings.Split(strings.TrimPrefix(file, root+"/"), "/")
+		if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+			return fmt.Errorf("template %s is not laid out as <name>/v<version>/<locale>.tmpl", file)
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+		if err != nil {
+			return fmt.Errorf("template %s has an invalid version: %w", file, err)
+		}
+
+		content, err := fs.ReadFile(source, file)
+		if err != nil {
+			return err
+		}
+		parsed, err := parseVariant(file, string(content))
+		if err != nil {
+			return err
+		}
+
+		name, locale := parts[0], strings.TrimSuffix(parts[2], ".tmpl")
+		if r.variants[name] == nil {
+			r.variants[name] = map[int]map[string]*variant{}
+		}
+		if r.variants[name][version] == nil {
+			r.variants[name][version] = map[string]*variant{}
+		}
+		r.variants[name][version][locale] = parsed
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification templates: %w", err)
+	}
+	return r, nil
+}
+
+func parseVariant(file, content string) (*variant, error) {
+	text, err := texttemplate.New(file).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if text.Lookup("subject") == nil || text.Lookup("text") == nil {
+		return nil, fmt.Errorf("template %s must define subject and text blocks", file)
+	}
+	html, err := htmltemplate.New(file).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return &variant{text: text, html: html}, nil
+}
+
+// Render executes the template in the best matching locale: the exact locale, then its
+// base language (ne-NP -> ne), then the default locale. A zero version means the latest.
+func (r *Renderer) Render(name, locale string, version int, data interface{}) (*Rendered, error) {
+	versions, ok := r.variants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if version == 0 {
+		version = latest(versions)
+	}
+	locales, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s v%d", ErrTemplateNotFound, name, version)
+	}
+
+	resolved, tmpl := r.resolveLocale(locales, locale)
+	if tmpl == nil {
+		return nil, fmt.Errorf("%w: %s v%d has no %s or %s variant", ErrTemplateNotFound, name, version, locale, r.defaultLocale)
+	}
+
+	rendered := &Rendered{Name: name, Version: version, Locale: resolved}
+	var err error
+	if rendered.Subject, err = executeText(tmpl.text, "subject", data); err != nil {
+		return nil, err
+	}
+	if rendered.Text, err = executeText(tmpl.text, "text", data); err != nil {
+		return nil, err
+	}
+	if tmpl.html.Lookup("html") != nil {
+		var buf bytes.Buffer
+		if err := tmpl.html.ExecuteTemplate(&buf, "html", data); err != nil {
+			return nil, fmt.Errorf("failed to render %s html: %w", name, err)
+		}
+		rendered.HTML = strings.TrimSpace(buf.String())
+	}
+	return rendered, nil
+}
+
+func (r *Renderer) resolveLocale(locales map[string]*variant, locale string) (string, *variant) {
+	candidates := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, r.defaultLocale)
+
+	for _, candidate := range candidates {
+		if tmpl, ok := locales[candidate]; ok {
+			return candidate, tmpl
+		}
+	}
+	return "", nil
+}
+
+// List describes the available templates, sorted by name
+func (r *Renderer) List() []Info {
+	infos := make([]Info, 0, len(r.variants))
+	for name, versions := range r.variants {
+		info := Info{Name: name}
+		for version := range versions {
+			info.Versions = append(info.Versions, version)
+		}
+		sort.Ints(info.Versions)
+		for locale := range versions[latest(versions)] {
+			info.Locales = append(info.Locales, locale)
+		}
+		sort.Strings(info.Locales)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+func executeText(tmpl *texttemplate.Template, block string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", block, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func latest(versions map[int]map[string]*variant) int {
+	highest := 0
+	for version := range versions {
+		if version > highest {
+			highest = version
+		}
+	}
+	return highest
+}
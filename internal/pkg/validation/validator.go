@@ -3,12 +3,16 @@ package validation
 import (
 	"encoding/json"
 	"regexp"
+	"ride-sharing/internal/pkg/password"
 	"strings"
 	"unicode"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// passwordPolicy backs the strongpassword tag, replaced when validators are registered
+var passwordPolicy = password.DefaultPolicy()
+
 // ProcessValidationError creates a structured error response from validator errors
 func ProcessValidationError(err error) map[string]string {
 	errors := make(map[string]string)
@@ -32,19 +36,31 @@ func ProcessValidationError(err error) map[string]string {
 				errors[jsonName] = "Must be less than " + param + " characters"
 			case "email":
 				errors[jsonName] = "Must be a valid email address"
+			case "required_without":
+				errors[jsonName] = "Required when " + toSnakeCase(param) + " is not provided"
 			case "eqfield":
 				targetField := toSnakeCase(param)
 				errors[jsonName] = "Must match " + targetField
+			case "oneof":
+				errors[jsonName] = "Must be one of: " + strings.ReplaceAll(param, " ", ", ")
 			case "numeric":
 				errors[jsonName] = "Must be a number"
 			case "alphanum":
 				errors[jsonName] = "Must contain only letters and numbers"
 			case "e164":
 				errors[jsonName] = "Must be a valid phone number in E.164 format"
+			case "bcp47_language_tag":
+				errors[jsonName] = "Must be a language tag such as en or ne-NP"
+			case "datetime":
+				errors[jsonName] = "Must match the format " + param
+			case "timezone":
+				errors[jsonName] = "Must be an IANA time zone such as Asia/Kathmandu"
 			case "strongpassword":
 				errors[jsonName] = GetPasswordRules()
 			case "otpvalidation":
 				errors[jsonName] = GetOTPRules()
+			case "flagkey":
+				errors[jsonName] = "Must be lowercase letters, digits and underscores, starting with a letter"
 			default:
 				errors[jsonName] = "Invalid value (" + tag + ")"
 			}
@@ -93,7 +109,11 @@ func toSnakeCase(camel string) string {
 }
 
 // RegisterCustomValidators adds all custom validators to the provided validator instance
-func RegisterCustomValidators(v *validator.Validate) error {
+func RegisterCustomValidators(v *validator.Validate, policy *password.Policy) error {
+	if policy != nil {
+		passwordPolicy = policy
+	}
+
 	// Register strongPassword validator with proper error handling
 	if err := v.RegisterValidation("strongpassword", validateStrongPassword); err != nil {
 		return err
@@ -101,19 +121,14 @@ func RegisterCustomValidators(v *validator.Validate) error {
 	if err := v.RegisterValidation("otpvalidation", validateOTP); err != nil {
 		return err
 	}
+	if err := v.RegisterValidation("flagkey", validateFlagKey); err != nil {
+		return err
+	}
 	return nil
 }
 
 func validateStrongPassword(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-
-	// Optimized checks using regex for better performance
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasDigit := regexp.MustCompile(`[0-9]`).MatchString(password)
-	hasSpecial := regexp.MustCompile(`[^a-zA-Z0-9]`).MatchString(password)
-
-	return len(password) >= 8 && hasUpper && hasLower && hasDigit && hasSpecial
+	return passwordPolicy.MeetsRules(fl.Field().String())
 }
 
 func validateOTP(fl validator.FieldLevel) bool {
@@ -125,9 +140,16 @@ func validateOTP(fl validator.FieldLevel) bool {
 	return match
 }
 
+var flagKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateFlagKey keeps feature flag keys usable as stable identifiers in code
+func validateFlagKey(fl validator.FieldLevel) bool {
+	return flagKeyPattern.MatchString(fl.Field().String())
+}
+
 // GetPasswordRules returns a description of password requirements for API docs/errors
 func GetPasswordRules() string {
-	return "Password must contain at least 8 characters including: 1 uppercase, 1 lowercase, 1 digit, and 1 special character"
+	return passwordPolicy.Description()
 }
 
 func GetOTPRules() string {
@@ -9,4 +9,4 @@ OUTPUT_DIR=.
 cd internal/proto
 protoc --go_out=. --go_opt=paths=source_relative \
        --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-       service.proto
+       service.proto auth.proto
@@ -396,6 +396,66 @@ func (x *ForgetPasswordEmailRequest) GetOtp() string {
 	return ""
 }
 
+type LoginCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	To            string                 `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	Otp           string                 `protobuf:"bytes,3,opt,name=otp,proto3" json:"otp,omitempty"`
+	Channel       string                 `protobuf:"bytes,4,opt,name=channel,proto3" json:"channel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginCodeRequest) Reset() {
+	*x = LoginCodeRequest{}
+	mi := &file_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginCodeRequest) ProtoMessage() {}
+
+func (x *LoginCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginCodeRequest.ProtoReflect.Descriptor instead.
+func (*LoginCodeRequest) Descriptor() ([]byte, []int) {
+	return file_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LoginCodeRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *LoginCodeRequest) GetOtp() string {
+	if x != nil {
+		return x.Otp
+	}
+	return ""
+}
+
+func (x *LoginCodeRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
 type PushRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceToken   string                 `protobuf:"bytes,1,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
@@ -408,7 +468,7 @@ type PushRequest struct {
 
 func (x *PushRequest) Reset() {
 	*x = PushRequest{}
-	mi := &file_service_proto_msgTypes[6]
+	mi := &file_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -420,7 +480,7 @@ func (x *PushRequest) String() string {
 func (*PushRequest) ProtoMessage() {}
 
 func (x *PushRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_proto_msgTypes[6]
+	mi := &file_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -433,7 +493,7 @@ func (x *PushRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PushRequest.ProtoReflect.Descriptor instead.
 func (*PushRequest) Descriptor() ([]byte, []int) {
-	return file_service_proto_rawDescGZIP(), []int{6}
+	return file_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PushRequest) GetDeviceToken() string {
@@ -464,6 +524,114 @@ func (x *PushRequest) GetData() map[string]string {
 	return nil
 }
 
+type NotificationRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Channel         string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"` // EMAIL or SMS
+	To              string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Subject         string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"` // ignored for SMS
+	BodyText        string                 `protobuf:"bytes,4,opt,name=body_text,json=bodyText,proto3" json:"body_text,omitempty"`
+	BodyHtml        string                 `protobuf:"bytes,5,opt,name=body_html,json=bodyHtml,proto3" json:"body_html,omitempty"` // optional, EMAIL only
+	Template        string                 `protobuf:"bytes,6,opt,name=template,proto3" json:"template,omitempty"`                 // template name and version, for tracking
+	TemplateVersion int32                  `protobuf:"varint,7,opt,name=template_version,json=templateVersion,proto3" json:"template_version,omitempty"`
+	Locale          string                 `protobuf:"bytes,8,opt,name=locale,proto3" json:"locale,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NotificationRequest) Reset() {
+	*x = NotificationRequest{}
+	mi := &file_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationRequest) ProtoMessage() {}
+
+func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
+func (*NotificationRequest) Descriptor() ([]byte, []int) {
+	return file_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NotificationRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetBodyText() string {
+	if x != nil {
+		return x.BodyText
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetBodyHtml() string {
+	if x != nil {
+		return x.BodyHtml
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetTemplateVersion() int32 {
+	if x != nil {
+		return x.TemplateVersion
+	}
+	return 0
+}
+
+func (x *NotificationRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 var File_service_proto protoreflect.FileDescriptor
 
 const file_service_proto_rawDesc = "" +
@@ -495,7 +663,11 @@ const file_service_proto_rawDesc = "" +
 	"\x03otp\x18\x03 \x01(\tR\x03otp\">\n" +
 	"\x1aForgetPasswordEmailRequest\x12\x0e\n" +
 	"\x02to\x18\x01 \x01(\tR\x02to\x12\x10\n" +
-	"\x03otp\x18\x03 \x01(\tR\x03otp\"\xcc\x01\n" +
+	"\x03otp\x18\x03 \x01(\tR\x03otp\"N\n" +
+	"\x10LoginCodeRequest\x12\x0e\n" +
+	"\x02to\x18\x01 \x01(\tR\x02to\x12\x10\n" +
+	"\x03otp\x18\x03 \x01(\tR\x03otp\x12\x18\n" +
+	"\achannel\x18\x04 \x01(\tR\achannel\"\xcc\x01\n" +
 	"\vPushRequest\x12!\n" +
 	"\fdevice_token\x18\x01 \x01(\tR\vdeviceToken\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
@@ -503,11 +675,26 @@ const file_service_proto_rawDesc = "" +
 	"\x04data\x18\x04 \x03(\v2#.notification.PushRequest.DataEntryR\x04data\x1a7\n" +
 	"\tDataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x9a\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfc\x02\n" +
+	"\x13NotificationRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x1b\n" +
+	"\tbody_text\x18\x04 \x01(\tR\bbodyText\x12\x1b\n" +
+	"\tbody_html\x18\x05 \x01(\tR\bbodyHtml\x12\x1a\n" +
+	"\btemplate\x18\x06 \x01(\tR\btemplate\x12)\n" +
+	"\x10template_version\x18\a \x01(\x05R\x0ftemplateVersion\x12\x16\n" +
+	"\x06locale\x18\b \x01(\tR\x06locale\x12K\n" +
+	"\bmetadata\x18\t \x03(\v2/.notification.NotificationRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xc2\x03\n" +
 	"\x13NotificationService\x12W\n" +
 	"\x11SendRegisterEmail\x12\".notification.RegisterEmailRequest\x1a\x1e.notification.StandardResponse\x12c\n" +
 	"\x17SendForgetPasswordEmail\x12(.notification.ForgetPasswordEmailRequest\x1a\x1e.notification.StandardResponse\x12E\n" +
-	"\bSendPush\x12\x19.notification.PushRequest\x1a\x1e.notification.StandardResponseB\x1dZ\x1bride-sharing/internal/protob\x06proto3"
+	"\bSendPush\x12\x19.notification.PushRequest\x1a\x1e.notification.StandardResponse\x12O\n" +
+	"\rSendLoginCode\x12\x1e.notification.LoginCodeRequest\x1a\x1e.notification.StandardResponse\x12U\n" +
+	"\x10SendNotification\x12!.notification.NotificationRequest\x1a\x1e.notification.StandardResponseB\x1dZ\x1bride-sharing/internal/protob\x06proto3"
 
 var (
 	file_service_proto_rawDescOnce sync.Once
@@ -521,7 +708,7 @@ func file_service_proto_rawDescGZIP() []byte {
 	return file_service_proto_rawDescData
 }
 
-var file_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_service_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_service_proto_goTypes = []any{
 	(*StandardResponse)(nil),           // 0: notification.StandardResponse
 	(*DataResponse)(nil),               // 1: notification.DataResponse
@@ -529,29 +716,37 @@ var file_service_proto_goTypes = []any{
 	(*MetaData)(nil),                   // 3: notification.MetaData
 	(*RegisterEmailRequest)(nil),       // 4: notification.RegisterEmailRequest
 	(*ForgetPasswordEmailRequest)(nil), // 5: notification.ForgetPasswordEmailRequest
-	(*PushRequest)(nil),                // 6: notification.PushRequest
-	nil,                                // 7: notification.ErrorResponse.DetailsEntry
-	nil,                                // 8: notification.PushRequest.DataEntry
-	(*anypb.Any)(nil),                  // 9: google.protobuf.Any
+	(*LoginCodeRequest)(nil),           // 6: notification.LoginCodeRequest
+	(*PushRequest)(nil),                // 7: notification.PushRequest
+	(*NotificationRequest)(nil),        // 8: notification.NotificationRequest
+	nil,                                // 9: notification.ErrorResponse.DetailsEntry
+	nil,                                // 10: notification.PushRequest.DataEntry
+	nil,                                // 11: notification.NotificationRequest.MetadataEntry
+	(*anypb.Any)(nil),                  // 12: google.protobuf.Any
 }
 var file_service_proto_depIdxs = []int32{
-	1, // 0: notification.StandardResponse.data:type_name -> notification.DataResponse
-	2, // 1: notification.StandardResponse.error:type_name -> notification.ErrorResponse
-	9, // 2: notification.DataResponse.payload:type_name -> google.protobuf.Any
-	3, // 3: notification.DataResponse.meta:type_name -> notification.MetaData
-	7, // 4: notification.ErrorResponse.details:type_name -> notification.ErrorResponse.DetailsEntry
-	8, // 5: notification.PushRequest.data:type_name -> notification.PushRequest.DataEntry
-	4, // 6: notification.NotificationService.SendRegisterEmail:input_type -> notification.RegisterEmailRequest
-	5, // 7: notification.NotificationService.SendForgetPasswordEmail:input_type -> notification.ForgetPasswordEmailRequest
-	6, // 8: notification.NotificationService.SendPush:input_type -> notification.PushRequest
-	0, // 9: notification.NotificationService.SendRegisterEmail:output_type -> notification.StandardResponse
-	0, // 10: notification.NotificationService.SendForgetPasswordEmail:output_type -> notification.StandardResponse
-	0, // 11: notification.NotificationService.SendPush:output_type -> notification.StandardResponse
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	1,  // 0: notification.StandardResponse.data:type_name -> notification.DataResponse
+	2,  // 1: notification.StandardResponse.error:type_name -> notification.ErrorResponse
+	12, // 2: notification.DataResponse.payload:type_name -> google.protobuf.Any
+	3,  // 3: notification.DataResponse.meta:type_name -> notification.MetaData
+	9,  // 4: notification.ErrorResponse.details:type_name -> notification.ErrorResponse.DetailsEntry
+	10, // 5: notification.PushRequest.data:type_name -> notification.PushRequest.DataEntry
+	11, // 6: notification.NotificationRequest.metadata:type_name -> notification.NotificationRequest.MetadataEntry
+	4,  // 7: notification.NotificationService.SendRegisterEmail:input_type -> notification.RegisterEmailRequest
+	5,  // 8: notification.NotificationService.SendForgetPasswordEmail:input_type -> notification.ForgetPasswordEmailRequest
+	7,  // 9: notification.NotificationService.SendPush:input_type -> notification.PushRequest
+	6,  // 10: notification.NotificationService.SendLoginCode:input_type -> notification.LoginCodeRequest
+	8,  // 11: notification.NotificationService.SendNotification:input_type -> notification.NotificationRequest
+	0,  // 12: notification.NotificationService.SendRegisterEmail:output_type -> notification.StandardResponse
+	0,  // 13: notification.NotificationService.SendForgetPasswordEmail:output_type -> notification.StandardResponse
+	0,  // 14: notification.NotificationService.SendPush:output_type -> notification.StandardResponse
+	0,  // 15: notification.NotificationService.SendLoginCode:output_type -> notification.StandardResponse
+	0,  // 16: notification.NotificationService.SendNotification:output_type -> notification.StandardResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_service_proto_init() }
@@ -569,7 +764,7 @@ func file_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_proto_rawDesc), len(file_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,8 @@ const (
 	NotificationService_SendRegisterEmail_FullMethodName       = "/notification.NotificationService/SendRegisterEmail"
 	NotificationService_SendForgetPasswordEmail_FullMethodName = "/notification.NotificationService/SendForgetPasswordEmail"
 	NotificationService_SendPush_FullMethodName                = "/notification.NotificationService/SendPush"
+	NotificationService_SendLoginCode_FullMethodName           = "/notification.NotificationService/SendLoginCode"
+	NotificationService_SendNotification_FullMethodName        = "/notification.NotificationService/SendNotification"
 )
 
 // NotificationServiceClient is the client API for NotificationService service.
@@ -31,6 +33,9 @@ type NotificationServiceClient interface {
 	SendRegisterEmail(ctx context.Context, in *RegisterEmailRequest, opts ...grpc.CallOption) (*StandardResponse, error)
 	SendForgetPasswordEmail(ctx context.Context, in *ForgetPasswordEmailRequest, opts ...grpc.CallOption) (*StandardResponse, error)
 	SendPush(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*StandardResponse, error)
+	SendLoginCode(ctx context.Context, in *LoginCodeRequest, opts ...grpc.CallOption) (*StandardResponse, error)
+	// SendNotification delivers content rendered by the caller on any channel
+	SendNotification(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*StandardResponse, error)
 }
 
 type notificationServiceClient struct {
@@ -71,6 +76,26 @@ func (c *notificationServiceClient) SendPush(ctx context.Context, in *PushReques
 	return out, nil
 }
 
+func (c *notificationServiceClient) SendLoginCode(ctx context.Context, in *LoginCodeRequest, opts ...grpc.CallOption) (*StandardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StandardResponse)
+	err := c.cc.Invoke(ctx, NotificationService_SendLoginCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) SendNotification(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*StandardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StandardResponse)
+	err := c.cc.Invoke(ctx, NotificationService_SendNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NotificationServiceServer is the server API for NotificationService service.
 // All implementations must embed UnimplementedNotificationServiceServer
 // for forward compatibility.
@@ -78,6 +103,9 @@ type NotificationServiceServer interface {
 	SendRegisterEmail(context.Context, *RegisterEmailRequest) (*StandardResponse, error)
 	SendForgetPasswordEmail(context.Context, *ForgetPasswordEmailRequest) (*StandardResponse, error)
 	SendPush(context.Context, *PushRequest) (*StandardResponse, error)
+	SendLoginCode(context.Context, *LoginCodeRequest) (*StandardResponse, error)
+	// SendNotification delivers content rendered by the caller on any channel
+	SendNotification(context.Context, *NotificationRequest) (*StandardResponse, error)
 	mustEmbedUnimplementedNotificationServiceServer()
 }
 
@@ -97,6 +125,12 @@ func (UnimplementedNotificationServiceServer) SendForgetPasswordEmail(context.Co
 func (UnimplementedNotificationServiceServer) SendPush(context.Context, *PushRequest) (*StandardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendPush not implemented")
 }
+func (UnimplementedNotificationServiceServer) SendLoginCode(context.Context, *LoginCodeRequest) (*StandardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendLoginCode not implemented")
+}
+func (UnimplementedNotificationServiceServer) SendNotification(context.Context, *NotificationRequest) (*StandardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendNotification not implemented")
+}
 func (UnimplementedNotificationServiceServer) mustEmbedUnimplementedNotificationServiceServer() {}
 func (UnimplementedNotificationServiceServer) testEmbeddedByValue()                             {}
 
@@ -172,6 +206,42 @@ func _NotificationService_SendPush_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotificationService_SendLoginCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).SendLoginCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_SendLoginCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).SendLoginCode(ctx, req.(*LoginCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_SendNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).SendNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_SendNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).SendNotification(ctx, req.(*NotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NotificationService_ServiceDesc is the grpc.ServiceDesc for NotificationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +261,14 @@ var NotificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendPush",
 			Handler:    _NotificationService_SendPush_Handler,
 		},
+		{
+			MethodName: "SendLoginCode",
+			Handler:    _NotificationService_SendLoginCode_Handler,
+		},
+		{
+			MethodName: "SendNotification",
+			Handler:    _NotificationService_SendNotification_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
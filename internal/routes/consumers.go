@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"ride-sharing/config"
+	"ride-sharing/internal/pkg/events"
+	"ride-sharing/internal/pkg/kafka"
+	"ride-sharing/internal/pkg/logging"
+	"ride-sharing/internal/pkg/metrics"
+
+	"go.uber.org/zap"
+)
+
+// SetupConsumer registers the handlers for events produced by other services.
+// The producer forwards failed messages to retry and dead-letter topics.
+func SetupConsumer(cfg *config.Config, producer *kafka.Producer) *kafka.Consumer {
+	consumer := kafka.NewConsumer(kafka.ConsumerConfig{
+		Brokers:      cfg.Kafka.Brokers,
+		GroupID:      cfg.Kafka.ConsumerGroup,
+		Topics:       cfg.Kafka.ConsumerTopics,
+		Concurrency:  cfg.Kafka.ConsumerConcurrency,
+		MaxAttempts:  cfg.Kafka.ConsumerMaxAttempts,
+		RetryBackoff: time.Duration(cfg.Kafka.ConsumerRetryBackoffMs) * time.Millisecond,
+		// Leave part of the shutdown worker timeout for closing the reader
+		DrainTimeout: time.Duration(cfg.Shutdown.WorkerTimeoutMs) * time.Millisecond * 4 / 5,
+	}, producer)
+
+	events.Handle(consumer, events.PaymentSettled, func(ctx context.Context, envelope events.Envelope, data events.PaymentSettledData) error {
+		// Trips are not persisted by this service yet, so settlement is only recorded in the logs
+		logging.GetLogger().WithContext(ctx).Info("payment settled",
+			zap.String("event_id", envelope.ID),
+			zap.String("payment_id", data.PaymentID),
+			zap.String("trip_id", data.TripID),
+		)
+		return nil
+	})
+
+	events.Handle(consumer, events.NotificationBounced, func(ctx context.Context, envelope events.Envelope, data events.NotificationBouncedData) error {
+		logging.GetLogger().WithContext(ctx).Warn("notification bounced",
+			zap.String("event_id", envelope.ID),
+			zap.String("channel", data.Channel),
+			zap.String("reason", data.Reason),
+		)
+		return nil
+	})
+
+	// Trips are owned by the trip service; only their lifecycle is counted here
+	events.Handle(consumer, events.TripRequested, func(ctx context.Context, envelope events.Envelope, data events.TripRequestedData) error {
+		metrics.TripsTotal.WithLabelValues("requested").Inc()
+		return nil
+	})
+	events.Handle(consumer, events.TripCompleted, func(ctx context.Context, envelope events.Envelope, data events.TripCompletedData) error {
+		metrics.TripsTotal.WithLabelValues("completed").Inc()
+		return nil
+	})
+
+	return consumer
+}
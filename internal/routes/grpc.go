@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"ride-sharing/config"
+	adminRepository "ride-sharing/internal/domains/admin/repository"
+	riderRepository "ride-sharing/internal/domains/riders/repository"
+	serviceAccountRepository "ride-sharing/internal/domains/serviceaccounts/repository"
+	serviceAccountService "ride-sharing/internal/domains/serviceaccounts/service"
+	"ride-sharing/internal/domains/users/repository"
+	"ride-sharing/internal/pkg/auth"
+	"ride-sharing/internal/pkg/grpcserver"
+	"ride-sharing/internal/pkg/redis"
+
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+// SetupGRPCServer wires the gRPC AuthService used by other services
+func SetupGRPCServer(db *gorm.DB, tokenService *auth.TokenService, rateLimiter *redis.RateLimiter, redisClient *redis.Client, cfg *config.Config) *grpc.Server {
+	userRepo := repository.NewUserRepository(db)
+	adminRepo := adminRepository.NewAdminRepository(db)
+	riderRepo := riderRepository.NewRiderRepository(db)
+	serviceAccountRepo := serviceAccountRepository.NewServiceAccountRepository(db)
+
+	userProviders := newUserProviders(userRepo, adminRepo, riderRepo)
+	serviceAccountSvc := serviceAccountService.NewServiceAccountService(serviceAccountRepo, rateLimiter)
+
+	authServer := grpcserver.NewAuthServer(tokenService, cachedUserProviders(userProviders, NewIdentityCache(redisClient, cfg)), userRepo, riderRepo)
+	return grpcserver.NewServer(authServer, serviceAccountSvc)
+}
@@ -2,14 +2,39 @@ package routes
 
 import (
 	"ride-sharing/config"
+	adminHttp "ride-sharing/internal/domains/admin/delivery/http"
+	adminProvider "ride-sharing/internal/domains/admin/provider"
+	adminRepository "ride-sharing/internal/domains/admin/repository"
+	adminService "ride-sharing/internal/domains/admin/service"
+	auditRepository "ride-sharing/internal/domains/audit/repository"
+	deviceHttp "ride-sharing/internal/domains/devices/delivery/http"
+	deviceRepository "ride-sharing/internal/domains/devices/repository"
+	deviceService "ride-sharing/internal/domains/devices/service"
+	featureFlagHttp "ride-sharing/internal/domains/featureflags/delivery/http"
+	featureFlagRepository "ride-sharing/internal/domains/featureflags/repository"
+	featureFlagService "ride-sharing/internal/domains/featureflags/service"
+	notificationHttp "ride-sharing/internal/domains/notifications/delivery/http"
+	notificationModels "ride-sharing/internal/domains/notifications/models"
+	notificationRepository "ride-sharing/internal/domains/notifications/repository"
+	notificationDomainService "ride-sharing/internal/domains/notifications/service"
+	riderProvider "ride-sharing/internal/domains/riders/provider"
+	riderRepository "ride-sharing/internal/domains/riders/repository"
+	serviceAccountHttp "ride-sharing/internal/domains/serviceaccounts/delivery/http"
+	serviceAccountRepository "ride-sharing/internal/domains/serviceaccounts/repository"
+	serviceAccountService "ride-sharing/internal/domains/serviceaccounts/service"
 	"ride-sharing/internal/domains/users/delivery/http"
 	"ride-sharing/internal/domains/users/repository"
 	"ride-sharing/internal/domains/users/service"
 	"ride-sharing/internal/pkg/auth"
 	email "ride-sharing/internal/pkg/grpcclient"
+	"ride-sharing/internal/pkg/health"
+	"ride-sharing/internal/pkg/metrics"
 	"ride-sharing/internal/pkg/middleware"
+	"ride-sharing/internal/pkg/password"
 	"ride-sharing/internal/pkg/provider"
 	"ride-sharing/internal/pkg/redis"
+	"ride-sharing/internal/pkg/templates"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -17,11 +42,11 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB, tokenService *auth.TokenService, otpStore *redis.OTPStore, notificationService *email.NotificationClient, cfg *config.Config) *gin.Engine {
+func SetupRouter(db *gorm.DB, tokenService *auth.TokenService, otpStore *redis.OTPStore, rateLimiter *redis.RateLimiter, inboxSvc *notificationDomainService.InboxService, redisClient *redis.Client, notificationService *email.NotificationClient, renderer *templates.Renderer, passwordPolicy *password.Policy, healthChecker *health.Checker, cfg *config.Config) *gin.Engine {
 	router := gin.Default()
-	router.Use(middleware.LoggingMiddleware(), gin.Recovery())
+	router.Use(middleware.TracingMiddleware(cfg.Log.ServiceName), middleware.LoggingMiddleware(), middleware.MetricsMiddleware(), gin.Recovery())
 
-	if cfg.Server.Environment != "production" {
+	if !cfg.IsProduction() {
 		// Create dynamic Swagger handler
 		swaggerHandler := ginSwagger.WrapHandler(
 			swaggerFiles.Handler,
@@ -30,16 +55,46 @@ func SetupRouter(db *gorm.DB, tokenService *auth.TokenService, otpStore *redis.O
 		)
 		router.GET("/swagger/*any", swaggerHandler)
 	}
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/health", health.Liveness)
+	router.GET("/ready", health.Readiness(healthChecker))
+	router.GET("/health/dependencies", health.Dependencies(healthChecker))
 	// Initialize dependencies
 	userRepo := repository.NewUserRepository(db)
+	adminRepo := adminRepository.NewAdminRepository(db)
+	riderRepo := riderRepository.NewRiderRepository(db)
+	serviceAccountRepo := serviceAccountRepository.NewServiceAccountRepository(db)
+	auditRepo := auditRepository.NewAuditRepository(db)
 	// Create user providers
-	userProviders := map[auth.UserType]auth.UserProvider{
-		auth.UserTypeUser: provider.NewUserProvider(userRepo),
-	}
-	userService := service.NewUserService(userRepo, tokenService, otpStore, notificationService, userProviders)
+	userProviders := newUserProviders(userRepo, adminRepo, riderRepo)
+	identityCache := NewIdentityCache(redisClient, cfg)
+	deviceSvc := deviceService.NewDeviceService(deviceRepository.NewDeviceRepository(db), notificationService)
+	deviceHandler := deviceHttp.NewDeviceHandler(deviceSvc)
+	preferenceRepo := notificationRepository.NewPreferenceRepository(db)
+	dispatcher := notificationDomainService.NewDispatcher(preferenceRepo)
+	dispatcher.RegisterSender(notificationModels.ChannelPush, notificationDomainService.NewPushSender(deviceSvc))
+	dispatcher.RegisterSender(notificationModels.ChannelInApp, notificationDomainService.NewInAppSender(inboxSvc))
+	notificationHandler := notificationHttp.NewNotificationHandler(notificationDomainService.NewPreferenceService(preferenceRepo), inboxSvc)
+	templateHandler := notificationHttp.NewTemplateHandler(renderer)
+	featureFlagSvc := featureFlagService.NewFeatureFlagService(
+		featureFlagRepository.NewFeatureFlagRepository(db),
+		redis.NewCache(redisClient, "featureflag:"),
+		time.Duration(cfg.FeatureFlags.CacheTTLSeconds)*time.Second,
+	)
+	featureFlagHandler := featureFlagHttp.NewFeatureFlagHandler(featureFlagSvc)
+	userService := service.NewUserService(userRepo, tokenService, otpStore, notificationService, userProviders, passwordPolicy, dispatcher, featureFlagSvc, identityCache)
 	userHandler := http.NewUserHandler(userService)
+	adminSvc := adminService.NewAdminService(adminRepo, tokenService, userProviders, auditRepo)
+	adminHandler := adminHttp.NewAdminHandler(adminSvc)
+	serviceAccountSvc := serviceAccountService.NewServiceAccountService(serviceAccountRepo, rateLimiter)
+	serviceAccountHandler := serviceAccountHttp.NewServiceAccountHandler(serviceAccountSvc)
 
-	authMiddleware := middleware.NewAuthMiddleware(tokenService, userProviders)
+	authMiddleware := middleware.NewAuthMiddleware(tokenService, cachedUserProviders(userProviders, identityCache), serviceAccountSvc, auditRepo)
+	// Lets clients on flaky networks retry mutating requests safely
+	idempotency := middleware.Idempotency(redis.NewIdempotencyStore(redisClient), middleware.IdempotencyConfig{
+		LockTimeout: time.Duration(cfg.Idempotency.LockTimeoutSeconds) * time.Second,
+		Retention:   time.Duration(cfg.Idempotency.RetentionHours) * time.Hour,
+	})
 
 	// API versioning
 	api := router.Group("/api/v1")
@@ -47,22 +102,112 @@ func SetupRouter(db *gorm.DB, tokenService *auth.TokenService, otpStore *redis.O
 	// Public user routes
 	userRoutes := api.Group("/users")
 	{
-		userRoutes.POST("/register", userHandler.Register)
+		userRoutes.POST("/register", idempotency, userHandler.Register)
 		userRoutes.POST("/login", userHandler.Login)
 		userRoutes.POST("/refresh", userHandler.Refresh)
 		userRoutes.POST("/forget-password", userHandler.ForgetPassword)
 		userRoutes.POST("/verify-reset", userHandler.VerifyForgetPassword)
 		userRoutes.POST("/verify-email", userHandler.VerifyEmail)
+		userRoutes.POST("/login-code", userHandler.RequestLoginCode)
+		userRoutes.POST("/login-code/verify", userHandler.VerifyLoginCode)
+		userRoutes.GET("/password-policy", userHandler.PasswordPolicy)
 
 	}
 
 	// Protected user routes
 	authRoutes := api.Group("/users")
-	authRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeUser))
+	authRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeUser), idempotency)
 	{
-		authRoutes.POST("/change-password", userHandler.ChangePassword)
 		authRoutes.GET("/profile", userHandler.UserProfile)
+		authRoutes.PUT("/locale", userHandler.UpdateLocale)
+	}
+
+	// Users whose password expired can still reach this one to change it
+	passwordRoutes := api.Group("/users")
+	passwordRoutes.Use(authMiddleware.AuthenticatePasswordChange(), middleware.RequireUserType(auth.UserTypeUser), idempotency)
+	{
+		passwordRoutes.POST("/change-password", middleware.DenyImpersonation(), userHandler.ChangePassword)
+	}
+
+	// Push devices for users and riders
+	deviceRoutes := api.Group("/devices")
+	deviceRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeUser, auth.UserTypeRider), idempotency)
+	{
+		deviceRoutes.POST("", deviceHandler.Register)
+		deviceRoutes.GET("", deviceHandler.List)
+		deviceRoutes.DELETE("/:id", deviceHandler.Unregister)
+	}
+
+	// Notification preferences and inbox for users and riders
+	notificationRoutes := api.Group("/notifications")
+	notificationRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeUser, auth.UserTypeRider))
+	{
+		notificationRoutes.GET("/preferences", notificationHandler.GetPreferences)
+		notificationRoutes.PUT("/preferences", notificationHandler.UpdatePreferences)
+		notificationRoutes.GET("/inbox", notificationHandler.ListInbox)
+		notificationRoutes.GET("/inbox/unread-count", notificationHandler.UnreadCount)
+		notificationRoutes.GET("/inbox/stream", notificationHandler.StreamInbox)
+		notificationRoutes.POST("/inbox/read-all", notificationHandler.MarkAllRead)
+		notificationRoutes.POST("/inbox/:id/read", notificationHandler.MarkRead)
+	}
+
+	// Public admin routes
+	api.POST("/admin/login", adminHandler.Login)
+
+	// Protected admin routes
+	adminRoutes := api.Group("/admin")
+	adminRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeAdmin), idempotency)
+	{
+		adminRoutes.POST("/impersonate", adminHandler.Impersonate)
+		adminRoutes.GET("/notification-templates", templateHandler.List)
+		adminRoutes.POST("/notification-templates/preview", templateHandler.Preview)
+		adminRoutes.POST("/service-accounts", serviceAccountHandler.CreateAccount)
+		adminRoutes.GET("/service-accounts", serviceAccountHandler.ListAccounts)
+		adminRoutes.POST("/service-accounts/:id/keys", serviceAccountHandler.CreateKey)
+		adminRoutes.POST("/service-accounts/:id/keys/:keyId/rotate", serviceAccountHandler.RotateKey)
+		adminRoutes.DELETE("/service-accounts/:id/keys/:keyId", serviceAccountHandler.RevokeKey)
+		adminRoutes.POST("/feature-flags", featureFlagHandler.Create)
+		adminRoutes.GET("/feature-flags", featureFlagHandler.List)
+		adminRoutes.GET("/feature-flags/:key", featureFlagHandler.Get)
+		adminRoutes.PATCH("/feature-flags/:key", featureFlagHandler.Update)
+		adminRoutes.DELETE("/feature-flags/:key", featureFlagHandler.Delete)
+		adminRoutes.POST("/feature-flags/:key/evaluate", featureFlagHandler.Evaluate)
+	}
+
+	// Partner routes authenticated with API keys
+	partnerRoutes := api.Group("/partner")
+	partnerRoutes.Use(authMiddleware.Authenticate(), middleware.RequireUserType(auth.UserTypeService), idempotency)
+	{
+		partnerRoutes.GET("/me", middleware.RequireScope(auth.ScopeAccountRead), serviceAccountHandler.Me)
 	}
 
 	return router
 }
+
+// newUserProviders maps every token holding account type to the provider that loads it
+func newUserProviders(userRepo repository.UserRepository, adminRepo adminRepository.AdminRepository, riderRepo riderRepository.RiderRepository) map[auth.UserType]auth.UserProvider {
+	return map[auth.UserType]auth.UserProvider{
+		auth.UserTypeUser:  provider.NewUserProvider(userRepo),
+		auth.UserTypeAdmin: adminProvider.NewAdminProvider(adminRepo),
+		auth.UserTypeRider: riderProvider.NewRiderProvider(riderRepo),
+	}
+}
+
+// NewIdentityCache backs token validation with Redis, see provider.IdentityCache. The CLI
+// builds the same cache so its account changes invalidate what the server has cached.
+func NewIdentityCache(redisClient *redis.Client, cfg *config.Config) *provider.IdentityCache {
+	return provider.NewIdentityCache(
+		redis.NewVersionedCache(redisClient, "identity:"),
+		time.Duration(cfg.IdentityCache.TTLSeconds)*time.Second,
+	)
+}
+
+// cachedUserProviders wraps every provider with the identity cache. The cached providers
+// return snapshots, so they are only handed to code that checks sessions.
+func cachedUserProviders(providers map[auth.UserType]auth.UserProvider, identities *provider.IdentityCache) map[auth.UserType]auth.UserProvider {
+	cached := make(map[auth.UserType]auth.UserProvider, len(providers))
+	for userType, p := range providers {
+		cached[userType] = identities.Wrap(p)
+	}
+	return cached
+}